@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// Set with --accessible
+var accessibleFlag bool
+
+// Environment variable turning on accessible prompts, like --accessible
+const accessibleEnv = "LEDGER_LIVE_STARTER_ACCESSIBLE"
+
+// accessibleRequested reports whether line-based prompts were asked for explicitly
+func accessibleRequested() bool {
+	return accessibleFlag || os.Getenv(accessibleEnv) != ""
+}
+
+// accessibleMode reports whether forms use huh's line-based prompts instead of the
+// full-screen menus: when asked for, or when the output is not a terminal the menus
+// could be drawn on
+func accessibleMode() bool {
+	return accessibleRequested() || !term.IsTerminal(os.Stdout.Fd())
+}
+
+// canPrompt reports whether input can be prompted for: on a terminal, or line by line
+// from stdin when accessible mode was asked for
+func canPrompt() bool {
+	return term.IsTerminal(os.Stdin.Fd()) || accessibleRequested()
+}
+
+// requireTerminal stops with guidance when input cannot be prompted for because stdin is
+// not a terminal (CI, piped input), instead of letting the form fail halfway through
+func requireTerminal() {
+	if canPrompt() {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "%s %s\n", ErrorText("Error:"), NormalText("This needs an answer, but stdin is not a terminal so ledger-live cannot prompt for it."))
+	fmt.Fprintf(os.Stderr, "%s %s\n", InfoTextTitle("Info:"), NormalText("Run it in a terminal, pass --accessible to answer line-based prompts from stdin, or use the non-interactive commands and flags instead:"))
+	for _, hint := range [][2]string{
+		{"ledger-live start --preset <name>", "start a preset without the menu"},
+		{"ledger-live preset add|edit|rm", "manage presets"},
+		{"ledger-live param add|edit|rm", "manage parameters"},
+		{"ledger-live preset import --strategy", "import without conflict prompts"},
+		{"LEDGER_LIVE_STARTER_VAULT_PASSPHRASE", "unlock secret parameters"},
+	} {
+		fmt.Fprintf(os.Stderr, "  %s  %s\n", HighlightText(fmt.Sprintf("%-36s", hint[0])), NormalText(hint[1]))
+	}
+	os.Exit(1)
+}
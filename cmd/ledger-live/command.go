@@ -6,19 +6,58 @@ import (
 	"os/exec"
 	"strings"
 
+	"ledger-live-starter/cmd/ledger-live/setup"
 )
 
 type CommandInfo struct {
 	BaseCommand    string
+	Args           []string
 	EnvVars        map[string]string
+	SecretEnvVars  map[string]setup.Parameter // Env var name -> secret parameter, decrypted only at execution
 	WorkingDir     string
 }
 
-func buildCommand(baseCommand string, parameters []Parameter, config *Config) *CommandInfo {
+func buildCommand(baseCommand string, presetName string, parameters []Parameter, config *Config) (*CommandInfo, error) {
+	return buildCommandWithOverrides(baseCommand, presetName, parameters, nil, config.LedgerLivePath, config)
+}
+
+// buildCommandWithOverrides builds a command whose env overrides take precedence over parameter values
+func buildCommandWithOverrides(baseCommand string, presetName string, parameters []Parameter, overrides map[string]string, workingDir string, config *Config) (*CommandInfo, error) {
+	rawEnvVars, secretEnvVars := collectEnvVars(parameters)
+	for key, value := range overrides {
+		rawEnvVars[key] = value
+		delete(secretEnvVars, key)
+	}
+
+	// Expand ${VAR} references and $(command) substitutions
+	secretNames := make(map[string]bool)
+	for name := range secretEnvVars {
+		secretNames[name] = true
+	}
+	builtins := builtinVariables(config, presetName, setup.PlatformKey(baseCommand))
+	envVars, err := expandEnvVars(rawEnvVars, secretNames, builtins, workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommandInfo{
+		BaseCommand:   baseCommand,
+		EnvVars:       envVars,
+		SecretEnvVars: secretEnvVars,
+		WorkingDir:    workingDir,
+	}, nil
+}
+
+// collectEnvVars extracts environment variables from parameters, keeping secrets as references
+func collectEnvVars(parameters []Parameter) (map[string]string, map[string]setup.Parameter) {
 	envVars := make(map[string]string)
+	secretEnvVars := make(map[string]setup.Parameter)
 
-	// Extract environment variables from selected parameters
 	for _, param := range parameters {
+		if param.Secret {
+			secretEnvVars[setup.SecretEnvVarName(param)] = param
+			continue
+		}
 		// Parse "VAR_NAME=value" format
 		if strings.Contains(param.EnvVar, "=") {
 			parts := strings.SplitN(param.EnvVar, "=", 2)
@@ -28,11 +67,7 @@ func buildCommand(baseCommand string, parameters []Parameter, config *Config) *C
 		}
 	}
 
-	return &CommandInfo{
-		BaseCommand: baseCommand,
-		EnvVars:     envVars,
-		WorkingDir:  config.LedgerLivePath,
-	}
+	return envVars, secretEnvVars
 }
 
 func executeCommand(cmdInfo *CommandInfo) {
@@ -41,12 +76,16 @@ func executeCommand(cmdInfo *CommandInfo) {
 	for key, value := range cmdInfo.EnvVars {
 		displayParts = append(displayParts, fmt.Sprintf("%s=%s", key, value))
 	}
+	for key := range cmdInfo.SecretEnvVars {
+		displayParts = append(displayParts, fmt.Sprintf("%s=%s", key, setup.SecretMask))
+	}
 	
+	command := strings.Join(append([]string{cmdInfo.BaseCommand}, cmdInfo.Args...), " ")
 	displayCommand := strings.Join(displayParts, " ")
 	if len(displayParts) > 0 {
-		displayCommand += " " + cmdInfo.BaseCommand
+		displayCommand += " " + command
 	} else {
-		displayCommand = cmdInfo.BaseCommand
+		displayCommand = command
 	}
 	
 	fmt.Printf("\n%s %s\n", TitleText("Executing:"), HighlightText(displayCommand))
@@ -60,7 +99,7 @@ func executeCommand(cmdInfo *CommandInfo) {
 	}
 
 	// Create command
-	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd := exec.Command(parts[0], append(parts[1:], cmdInfo.Args...)...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
@@ -70,14 +109,24 @@ func executeCommand(cmdInfo *CommandInfo) {
 		cmd.Dir = cmdInfo.WorkingDir
 	}
 	
+	// Decrypt secret values only now, right before they are handed to the child process
+	secretValues, err := setup.ResolveSecrets(cmdInfo.SecretEnvVars)
+	if err != nil {
+		fmt.Printf("%s %s %s\n", ErrorText("Error:"), NormalText("Could not unlock secrets:"), NormalText(err.Error()))
+		return
+	}
+
 	// Set environment variables
 	cmd.Env = os.Environ() // Start with current environment
 	for key, value := range cmdInfo.EnvVars {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
 	}
+	for key, value := range secretValues {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+	}
 
 	// Execute command
-	err := cmd.Run()
+	err = cmd.Run()
 	if err != nil {
 		fmt.Printf("%s %s %s\n", ErrorText("Error:"), NormalText("Error executing command:"), NormalText(err.Error()))
 		os.Exit(1)
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion",
+	Short: "Generate or install shell completion scripts",
+	Long: `Generate completion scripts for bash, zsh, fish and PowerShell, or install them with
+'ledger-live completion install'. Preset, parameter and profile names are completed
+from your configuration.`,
+}
+
+// Supported shells and their script generators
+var completionShells = map[string]func(cmd *cobra.Command, out *bytes.Buffer) error{
+	"bash": func(cmd *cobra.Command, out *bytes.Buffer) error { return cmd.Root().GenBashCompletionV2(out, true) },
+	"zsh":  func(cmd *cobra.Command, out *bytes.Buffer) error { return cmd.Root().GenZshCompletion(out) },
+	"fish": func(cmd *cobra.Command, out *bytes.Buffer) error { return cmd.Root().GenFishCompletion(out, true) },
+	"powershell": func(cmd *cobra.Command, out *bytes.Buffer) error {
+		return cmd.Root().GenPowerShellCompletionWithDesc(out)
+	},
+}
+
+var completionInstallCmd = &cobra.Command{
+	Use:   "install [shell]",
+	Short: "Install the completion script for your shell",
+	Long: `Write the completion script where the shell loads it from. The shell is taken from
+$SHELL unless given. Fish loads the script automatically; for bash and zsh a line to add
+to your shell config is printed if needed.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Run:       runCompletionInstallCmd,
+}
+
+func runCompletionInstallCmd(cmd *cobra.Command, args []string) {
+	shell := filepath.Base(os.Getenv("SHELL"))
+	if len(args) > 0 {
+		shell = args[0]
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		exitWithError(err)
+	}
+	name := cmd.Root().Name()
+
+	var path, hint string
+	switch shell {
+	case "bash":
+		path = filepath.Join(homeDir, ".local", "share", "bash-completion", "completions", name)
+		hint = "Requires the bash-completion package. Without it, add to ~/.bashrc: source " + path
+	case "zsh":
+		path = filepath.Join(homeDir, ".ledger-live", "completions", "_"+name)
+		hint = fmt.Sprintf("Add to ~/.zshrc before compinit: fpath=(%s $fpath)", filepath.Dir(path))
+	case "fish":
+		path = filepath.Join(homeDir, ".config", "fish", "completions", name+".fish")
+	default:
+		exitWithError(fmt.Errorf("cannot install completion for shell '%s', pass bash, zsh or fish, or print a script with 'ledger-live completion <shell>'", shell))
+	}
+
+	var script bytes.Buffer
+	if err := completionShells[shell](cmd, &script); err != nil {
+		exitWithError(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		exitWithError(err)
+	}
+	if err := setup.WriteFileAtomic(path, script.Bytes(), 0644); err != nil {
+		exitWithError(err)
+	}
+
+	fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText(fmt.Sprintf("Installed %s completion to", shell)), HighlightText(path))
+	if hint != "" {
+		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText(hint))
+	}
+	fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Open a new shell to use it."))
+}
+
+// completionFunc is the signature of cobra's dynamic completion callbacks
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completionConfig loads the config quietly, nil if there is none. Cobra does not run the
+// initializers for completion requests, so the --config and --profile flags and their
+// environment variables are applied here.
+func completionConfig() *setup.Config {
+	if err := applyConfigSelection(); err != nil {
+		return nil
+	}
+	config, err := setup.PeekConfig()
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
+// completePresetNames completes a single preset name
+func completePresetNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config := completionConfig()
+	if config == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, preset := range config.Presets {
+		names = append(names, preset.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeParameterNames completes a single parameter name
+func completeParameterNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config := completionConfig()
+	if config == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, param := range config.Parameters {
+		names = append(names, fmt.Sprintf("%s\t%s", param.Name, setup.MaskedEnvVar(param)))
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfileNames completes a single profile name
+func completeProfileNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return setup.ListProfiles(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// firstArg limits a completion to the first positional argument
+func firstArg(complete completionFunc) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// remainingArgs completes names not given as arguments yet, for commands taking several
+func remainingArgs(complete completionFunc) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names, directive := complete(cmd, args, toComplete)
+		var remaining []string
+		for _, name := range names {
+			value, _, _ := strings.Cut(name, "\t")
+			if !containsString(args, value) {
+				remaining = append(remaining, name)
+			}
+		}
+		return remaining, directive
+	}
+}
+
+// completeValues completes a fixed list of values
+func completeValues(values ...string) completionFunc {
+	return cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		shell := shell
+		completionCmd.AddCommand(&cobra.Command{
+			Use:   shell,
+			Short: fmt.Sprintf("Print the %s completion script", shell),
+			Args:  cobra.NoArgs,
+			Run: func(cmd *cobra.Command, args []string) {
+				var script bytes.Buffer
+				if err := completionShells[shell](cmd, &script); err != nil {
+					exitWithError(err)
+				}
+				os.Stdout.Write(script.Bytes())
+			},
+		})
+	}
+	completionCmd.AddCommand(completionInstallCmd)
+	rootCmd.AddCommand(completionCmd)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and maintain the configuration file",
+	Long:  `Validate the configuration file, export its JSON Schema and roll back changes.`,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration for errors and hygiene issues",
+	Long: `Check the configuration file for errors such as unknown platforms, malformed
+env_var values, duplicate names and references to missing parameters, and warn
+about unused parameters, duplicate environment variables and empty presets.
+
+Exits with a non-zero status if errors are found.`,
+	Args: cobra.NoArgs,
+	Run:  runConfigValidateCmd,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the configuration file",
+	Long: `Print a JSON Schema describing the configuration file. Point your editor at it,
+or add "$schema" to config.json, to get autocompletion and inline validation.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		schema, err := setup.ConfigSchema()
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			os.Exit(1)
+		}
+		fmt.Println(string(schema))
+	},
+}
+
+var configUndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last change to the configuration",
+	Long: `Restore the configuration as it was before the last change. Run it again to
+step further back through the kept backups.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		backup, err := setup.UndoConfigChange(setup.GetConfigPath())
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			os.Exit(1)
+		}
+		fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText("Restored the configuration from"), HighlightText(backup.Time.Format("2006-01-02 15:04:05")))
+	},
+}
+
+var listBackups bool
+
+var configRestoreCmd = &cobra.Command{
+	Use:   "restore [number]",
+	Short: "List or restore configuration backups",
+	Long: `List the kept configuration backups with --list, or restore the backup with the
+given number. The current configuration is backed up first, so a restore can be undone.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runConfigRestoreCmd,
+}
+
+func runConfigRestoreCmd(cmd *cobra.Command, args []string) {
+	path := setup.GetConfigPath()
+	backups, err := setup.ListConfigBackups(path)
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		os.Exit(1)
+	}
+
+	if listBackups || len(args) == 0 {
+		if len(backups) == 0 {
+			fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("No backups available yet."))
+			return
+		}
+		fmt.Printf("%s\n", TitleText("Configuration backups (newest first):"))
+		for i, backup := range backups {
+			fmt.Printf("  %s %s %s\n", HighlightText(fmt.Sprintf("%2d", i+1)), NormalText(backup.Time.Format("2006-01-02 15:04:05")), InfoTextTitle(fmt.Sprintf("%d parameters, %d presets", backup.Parameters, backup.Presets)))
+		}
+		if len(args) == 0 {
+			fmt.Printf("\n%s %s\n", InfoTextTitle("Info:"), NormalText("Run 'ledger-live config restore <number>' to restore a backup."))
+		}
+		return
+	}
+
+	var number int
+	if _, err := fmt.Sscanf(args[0], "%d", &number); err != nil || number < 1 || number > len(backups) {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(fmt.Sprintf("Backup number must be between 1 and %d", len(backups))))
+		os.Exit(1)
+	}
+
+	backup := backups[number-1]
+	if err := setup.RestoreConfigBackup(path, backup); err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		os.Exit(1)
+	}
+	fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText("Restored the configuration from"), HighlightText(backup.Time.Format("2006-01-02 15:04:05")))
+}
+
+var showOrigin bool
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Long: `Print the configuration after merging its layers, lowest precedence first:
+
+  1. The repository config (.ledger-live-starter.json in the Ledger Live repository)
+  2. The global config file (~/.ledger-live/config.json or --config)
+  3. Environment variables: LEDGER_LIVE_STARTER_ followed by a setting in upper case
+     overrides it, e.g. LEDGER_LIVE_STARTER_LEDGER_LIVE_PATH or
+     LEDGER_LIVE_STARTER_UPDATE_CHANNEL (settings: ledger-live-path, update_check,
+     update_endpoint, update_channel)
+
+Use --origin to see which layer each value came from.`,
+	Args: cobra.NoArgs,
+	Run:  runConfigShowCmd,
+}
+
+func runConfigShowCmd(cmd *cobra.Command, args []string) {
+	config, err := setup.LoadConfig()
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		os.Exit(1)
+	}
+
+	if !showOrigin {
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	for _, origin := range setup.ConfigOrigins(config) {
+		key := origin.Key
+		if origin.Value != "" {
+			key = fmt.Sprintf("%s = %s", key, origin.Value)
+		}
+		fmt.Printf("%s %s\n", HighlightText(key), InfoTextTitle(fmt.Sprintf("(%s: %s)", origin.Layer, origin.Source)))
+	}
+}
+
+var (
+	convertTo    string
+	convertForce bool
+)
+
+var configConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert the configuration to another file format",
+	Long: `Convert the configuration file to JSON, YAML or TOML. The converted file is
+written next to the original, which is kept with a .bak suffix.
+
+A file picked with --config or LEDGER_LIVE_STARTER_CONFIG is only converted with
+--force, since the setting keeps pointing at the moved original until you update it.`,
+	Example: `  ledger-live config convert --to yaml`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := setup.ParseConfigFormat(convertTo)
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			os.Exit(1)
+		}
+
+		oldPath := setup.GetConfigPath()
+		if setup.ConfigPathIsExplicit() && !convertForce {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(fmt.Sprintf("%s is set with --config or LEDGER_LIVE_STARTER_CONFIG, which would point at the moved original after converting. Use --force to convert anyway.", oldPath)))
+			os.Exit(1)
+		}
+		newPath, err := setup.ConvertConfigFile(oldPath, format)
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			os.Exit(1)
+		}
+		fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText("Configuration converted to"), HighlightText(newPath))
+		fmt.Printf("%s %s %s\n", InfoTextTitle("Info:"), NormalText("The original was kept as"), HighlightText(oldPath+".bak"))
+		if setup.ConfigPathIsExplicit() {
+			fmt.Printf("%s %s %s\n", WarningText("Warning:"), NormalText("Update your --config flag or LEDGER_LIVE_STARTER_CONFIG to"), HighlightText(newPath))
+		}
+	},
+}
+
+func runConfigValidateCmd(cmd *cobra.Command, args []string) {
+	path := setup.GetConfigPath()
+	report, err := setup.ValidateConfigFile(path)
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s %s\n\n", TitleText("Validating:"), HighlightText(path))
+	for _, issue := range report.Issues {
+		label := WarningText("warning")
+		if issue.Severity == setup.SeverityError {
+			label = ErrorText("error  ")
+		}
+		fmt.Printf("  %s %s %s\n", label, InfoTextTitle(issue.Path), NormalText(issue.Message))
+	}
+	if len(report.Issues) > 0 {
+		fmt.Println()
+	}
+
+	errors := report.Count(setup.SeverityError)
+	warnings := report.Count(setup.SeverityWarning)
+	summary := fmt.Sprintf("%d error(s), %d warning(s)", errors, warnings)
+	if errors > 0 {
+		fmt.Printf("%s %s\n", ErrorText("Invalid:"), NormalText(summary))
+		os.Exit(1)
+	}
+	fmt.Printf("%s %s\n", SuccessText("Valid:"), NormalText(summary))
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+	configCmd.AddCommand(configUndoCmd)
+
+	configShowCmd.Flags().BoolVar(&showOrigin, "origin", false, "show which layer each value came from")
+	configCmd.AddCommand(configShowCmd)
+
+	configConvertCmd.Flags().StringVar(&convertTo, "to", "", "target format: json, yaml or toml")
+	configConvertCmd.MarkFlagRequired("to")
+	configConvertCmd.Flags().BoolVar(&convertForce, "force", false, "convert a file picked with --config or LEDGER_LIVE_STARTER_CONFIG")
+	configConvertCmd.RegisterFlagCompletionFunc("to", completeValues("json", "yaml", "toml"))
+	configCmd.AddCommand(configConvertCmd)
+
+	configRestoreCmd.Flags().BoolVar(&listBackups, "list", false, "list the available backups")
+	configCmd.AddCommand(configRestoreCmd)
+	rootCmd.AddCommand(configCmd)
+}
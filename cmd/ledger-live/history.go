@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show which presets were started and when",
+	Long:  `Show how often each preset was started and when it last ran, most recent first.`,
+	Args:  cobra.NoArgs,
+	Run:   runHistoryCmd,
+}
+
+// HistoryOutput is the machine-readable output of `history`
+type HistoryOutput struct {
+	LastPreset string            `json:"last_preset,omitempty" yaml:"last_preset,omitempty"`
+	Presets    []PresetRunOutput `json:"presets" yaml:"presets"`
+}
+
+// PresetRunOutput is the run history of one preset
+type PresetRunOutput struct {
+	Name     string    `json:"name" yaml:"name"`
+	Runs     int       `json:"runs" yaml:"runs"`
+	LastUsed time.Time `json:"last_used" yaml:"last_used"`
+}
+
+func runHistoryCmd(cmd *cobra.Command, args []string) {
+	format := selectedOutputFormat()
+	usage := setup.LoadUsage()
+
+	// Usage is keyed by preset ID, show the current name or the last known one
+	var presets []setup.Preset
+	if config, err := setup.PeekConfig(); err == nil {
+		presets = config.Presets
+	}
+	presetName := func(key string) string {
+		if preset := setup.PresetByID(key, presets); preset != nil {
+			return preset.Name
+		}
+		if name := usage.Presets[key].Name; name != "" {
+			return name
+		}
+		return key
+	}
+
+	output := HistoryOutput{Presets: []PresetRunOutput{}}
+	if usage.LastPreset != "" {
+		output.LastPreset = presetName(usage.LastPreset)
+	}
+	for key, stats := range usage.Presets {
+		output.Presets = append(output.Presets, PresetRunOutput{Name: presetName(key), Runs: stats.Count, LastUsed: stats.LastUsed})
+	}
+	sort.Slice(output.Presets, func(i, j int) bool {
+		return output.Presets[i].LastUsed.After(output.Presets[j].LastUsed)
+	})
+
+	printOutput(format, output, func() {
+		if len(output.Presets) == 0 && format == OutputTable {
+			fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("No presets started yet."))
+			return
+		}
+		var rows [][]string
+		for _, run := range output.Presets {
+			rows = append(rows, []string{run.Name, fmt.Sprintf("%d", run.Runs), run.LastUsed.Local().Format("2006-01-02 15:04:05")})
+		}
+		printTable(format, []string{"Preset", "Runs", "Last run"}, rows)
+	})
+}
+
+func init() {
+	addOutputFlags(historyCmd)
+	rootCmd.AddCommand(historyCmd)
+}
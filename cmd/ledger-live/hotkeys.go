@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// hotkeyForm wraps a huh form so single key presses can pick a value directly
+type hotkeyForm struct {
+	form     *huh.Form
+	hotkeys  map[string]string
+	selected *string
+	chosen   bool
+}
+
+func (m *hotkeyForm) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m *hotkeyForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if value, ok := m.hotkeys[keyMsg.String()]; ok {
+			*m.selected = value
+			m.chosen = true
+			return m, tea.Quit
+		}
+	}
+
+	model, cmd := m.form.Update(msg)
+	if form, ok := model.(*huh.Form); ok {
+		m.form = form
+	}
+	return m, cmd
+}
+
+func (m *hotkeyForm) View() string {
+	if m.chosen {
+		return ""
+	}
+	return m.form.View()
+}
+
+// RunStyledFormWithHotkeys runs a themed form where pressing one of the hotkeys
+// immediately stores the mapped value in selected and closes the form. Accessible mode
+// has no hotkeys and runs the plain form.
+func RunStyledFormWithHotkeys(form *huh.Form, hotkeys map[string]string, selected *string) error {
+	if accessibleMode() {
+		return RunStyledForm(form)
+	}
+	requireTerminal()
+
+	form = form.WithTheme(GetCustomTheme())
+	form.SubmitCmd = tea.Quit
+	form.CancelCmd = tea.Quit
+
+	model := &hotkeyForm{form: form, hotkeys: hotkeys, selected: selected}
+	if _, err := tea.NewProgram(model).Run(); err != nil {
+		return fmt.Errorf("huh: %w", err)
+	}
+
+	if !model.chosen && form.State == huh.StateAborted {
+		return huh.ErrUserAborted
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Maximum time a $(command) substitution may take
+const substitutionTimeout = 10 * time.Second
+
+// interpolator expands ${VAR} references and $(command) substitutions in parameter values.
+// References are looked up in built-ins first, then in the other parameters of the same
+// command, then in the inherited environment.
+type interpolator struct {
+	builtins   map[string]string
+	raw        map[string]string // Unexpanded parameter values by env var name
+	secrets    map[string]bool   // Env var names of secret parameters, which cannot be referenced
+	workingDir string
+	resolved   map[string]string
+	stack      []string // Parameters currently being resolved, for cycle detection
+}
+
+// builtinVariables returns the values available to every parameter
+func builtinVariables(config *Config, presetName string, platform string) map[string]string {
+	homeDir, _ := os.UserHomeDir()
+	return map[string]string{
+		"LEDGER_LIVE_PATH": config.LedgerLivePath,
+		"PRESET":           presetName,
+		"PLATFORM":         platform,
+		"HOME":             homeDir,
+	}
+}
+
+// expandEnvVars resolves all parameter values, detecting reference cycles
+func expandEnvVars(raw map[string]string, secrets map[string]bool, builtins map[string]string, workingDir string) (map[string]string, error) {
+	in := &interpolator{
+		builtins:   builtins,
+		raw:        raw,
+		secrets:    secrets,
+		workingDir: workingDir,
+		resolved:   make(map[string]string),
+	}
+
+	for name := range raw {
+		if _, err := in.resolveParameter(name); err != nil {
+			return nil, err
+		}
+	}
+	return in.resolved, nil
+}
+
+// resolveParameter expands the value of one parameter, memoizing the result
+func (in *interpolator) resolveParameter(name string) (string, error) {
+	if value, ok := in.resolved[name]; ok {
+		return value, nil
+	}
+	for i, active := range in.stack {
+		if active == name {
+			cycle := append(append([]string{}, in.stack[i:]...), name)
+			return "", fmt.Errorf("reference cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	in.stack = append(in.stack, name)
+	value, err := in.expand(in.raw[name])
+	in.stack = in.stack[:len(in.stack)-1]
+	if err != nil {
+		// Name the parameter once, at the outermost level
+		if len(in.stack) == 0 {
+			return "", fmt.Errorf("%s: %v", name, err)
+		}
+		return "", err
+	}
+
+	in.resolved[name] = value
+	return value, nil
+}
+
+// lookup resolves a single ${NAME} reference
+func (in *interpolator) lookup(name string) (string, error) {
+	if value, ok := in.builtins[name]; ok {
+		return value, nil
+	}
+	if in.secrets[name] {
+		return "", fmt.Errorf("secret '%s' cannot be referenced", name)
+	}
+	// A parameter referencing its own name extends the inherited value (e.g. PATH=${PATH}:...)
+	isSelf := len(in.stack) > 0 && in.stack[len(in.stack)-1] == name
+	if _, ok := in.raw[name]; ok && !isSelf {
+		return in.resolveParameter(name)
+	}
+	// Unknown variables expand to an empty string, like in a shell
+	return os.Getenv(name), nil
+}
+
+// expand replaces ${NAME} and $(command) in a value. "$$" produces a literal "$".
+func (in *interpolator) expand(value string) (string, error) {
+	var result strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 >= len(value) {
+			result.WriteByte(value[i])
+			continue
+		}
+
+		switch value[i+1] {
+		case '$':
+			result.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(value[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated '${' in %q", value)
+			}
+			name := value[i+2 : i+2+end]
+			if name == "" {
+				return "", fmt.Errorf("empty '${}' in %q", value)
+			}
+			resolved, err := in.lookup(name)
+			if err != nil {
+				return "", err
+			}
+			result.WriteString(resolved)
+			i += end + 2
+		case '(':
+			end := matchingParenthesis(value, i+1)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated '$(' in %q", value)
+			}
+			// The command may itself use ${NAME} references
+			command, err := in.expand(value[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			output, err := runSubstitution(command, in.workingDir)
+			if err != nil {
+				return "", err
+			}
+			result.WriteString(output)
+			i = end
+		default:
+			result.WriteByte('$')
+		}
+	}
+
+	return result.String(), nil
+}
+
+// matchingParenthesis returns the index of the ')' closing the '(' at start, or -1
+func matchingParenthesis(value string, start int) int {
+	depth := 0
+	for i := start; i < len(value); i++ {
+		switch value[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// runSubstitution runs a $(command) in the workspace and returns its trimmed output
+func runSubstitution(command string, workingDir string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), substitutionTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	if workingDir != "" {
+		cmd.Dir = workingDir
+	}
+
+	output, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("$(%s) timed out after %s", command, substitutionTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("$(%s) failed: %v", command, err)
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
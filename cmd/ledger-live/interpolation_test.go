@@ -0,0 +1,130 @@
+package main
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestExpandEnvVars(t *testing.T) {
+	t.Setenv("LLS_TEST_INHERITED", "/usr/bin")
+	t.Setenv("LLS_TEST_UNSET", "")
+
+	tests := []struct {
+		name    string
+		raw     map[string]string
+		secrets map[string]bool
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "built-in",
+			raw:  map[string]string{"DIR": "${LEDGER_LIVE_PATH}/apps"},
+			want: map[string]string{"DIR": "/work/ledger-live/apps"},
+		},
+		{
+			name: "other parameter",
+			raw:  map[string]string{"HOST": "localhost", "URL": "http://${HOST}:${PORT}", "PORT": "8080"},
+			want: map[string]string{"HOST": "localhost", "URL": "http://localhost:8080", "PORT": "8080"},
+		},
+		{
+			name: "references prefer built-ins over parameters",
+			raw:  map[string]string{"PRESET": "shadowed", "NAME": "${PRESET}"},
+			want: map[string]string{"PRESET": "shadowed", "NAME": "Dev"},
+		},
+		{
+			name: "inherited and unknown variables",
+			raw:  map[string]string{"A": "${LLS_TEST_INHERITED}", "B": "[${LLS_TEST_UNSET}]"},
+			want: map[string]string{"A": "/usr/bin", "B": "[]"},
+		},
+		{
+			name: "dollar escaping",
+			raw:  map[string]string{"PRICE": "$$5 and $${HOST}", "TRAILING": "cost$", "LONE": "$x"},
+			want: map[string]string{"PRICE": "$5 and ${HOST}", "TRAILING": "cost$", "LONE": "$x"},
+		},
+		{
+			name: "self-reference extends the inherited value",
+			raw:  map[string]string{"LLS_TEST_INHERITED": "${LLS_TEST_INHERITED}:/opt/bin"},
+			want: map[string]string{"LLS_TEST_INHERITED": "/usr/bin:/opt/bin"},
+		},
+		{
+			name:    "direct cycle",
+			raw:     map[string]string{"A": "${B}", "B": "${A}"},
+			wantErr: "reference cycle:",
+		},
+		{
+			name:    "indirect cycle",
+			raw:     map[string]string{"A": "${B}", "B": "${C}", "C": "x${A}"},
+			wantErr: "reference cycle:",
+		},
+		{
+			name:    "secrets cannot be referenced",
+			raw:     map[string]string{"AUTH": "Bearer ${TOKEN}"},
+			secrets: map[string]bool{"TOKEN": true},
+			wantErr: "secret 'TOKEN' cannot be referenced",
+		},
+		{
+			name:    "unterminated reference",
+			raw:     map[string]string{"A": "${B"},
+			wantErr: "unterminated '${'",
+		},
+		{
+			name:    "empty reference",
+			raw:     map[string]string{"A": "${}"},
+			wantErr: "empty '${}'",
+		},
+	}
+
+	builtins := map[string]string{"LEDGER_LIVE_PATH": "/work/ledger-live", "PRESET": "Dev"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEnvVars(tt.raw, tt.secrets, builtins, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expandEnvVars() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandEnvVars() error = %v", err)
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %q, want %q", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandEnvVarsReportsCyclePath(t *testing.T) {
+	_, err := expandEnvVars(map[string]string{"A": "${A}${B}", "B": "${A}"}, nil, nil, "")
+	if err == nil {
+		t.Fatal("expected a reference cycle error")
+	}
+	if !strings.Contains(err.Error(), "A -> B -> A") && !strings.Contains(err.Error(), "B -> A -> B") {
+		t.Errorf("error %q does not name the cycle", err)
+	}
+}
+
+func TestExpandEnvVarsCommandSubstitution(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("substitutions run through sh")
+	}
+	dir := t.TempDir()
+
+	got, err := expandEnvVars(map[string]string{"WHERE": "$(pwd)", "GREETING": "$(echo hello ${NAME})", "NAME": "world"}, nil, nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["GREETING"] != "hello world" {
+		t.Errorf("GREETING = %q, want %q", got["GREETING"], "hello world")
+	}
+	if !strings.HasSuffix(got["WHERE"], dir) {
+		t.Errorf("WHERE = %q, want the working directory %s", got["WHERE"], dir)
+	}
+
+	if _, err := expandEnvVars(map[string]string{"A": "$(exit 3)"}, nil, nil, dir); err == nil {
+		t.Error("expected a failing substitution to be an error")
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"ledger-live-starter/cmd/ledger-live/parameters"
 	"ledger-live-starter/cmd/ledger-live/presets"
 	"ledger-live-starter/cmd/ledger-live/setup"
+	"ledger-live-starter/cmd/ledger-live/ui"
 
 )
 
 var configPath string // Global config path variable
+var profileName string // Global profile variable
 
 var rootCmd = &cobra.Command{
 	Use:   "ledger-live",
@@ -28,6 +30,22 @@ You can use it to quickly start mobile or desktop versions with predefined setti
 func init() {
 	// Add global config flag
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "config file path (default: ~/.ledger-live/config.json)")
+
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "profile to use (default: the one selected with 'profile use')")
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colors (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&accessibleFlag, "accessible", false, "use line-based prompts instead of full-screen menus, e.g. for screen readers")
+
+	// Pass the config path and profile to the setup package once flags are parsed
+	cobra.OnInitialize(func() {
+		if err := applyConfigSelection(); err != nil {
+			exitWithError(err)
+		}
+		// The theme may come from the profile's config, so apply it last
+		applyTheme()
+		ui.TextColor = palette.Text
+	})
 	
 	// Add version flag
 	var showVersion bool
@@ -44,8 +62,12 @@ func init() {
 		originalRun(cmd, args)
 	}
 	
+	// Set up the ui package dependencies
+	ui.BrandGradient = brandGradient
+
 	// Set up the setup package dependencies
 	setup.RunStyledForm = RunStyledForm
+	setup.CanPrompt = canPrompt
 	
 	// Set up theme-based text functions for setup package
 	setup.TitleText = TitleText
@@ -74,24 +96,31 @@ func init() {
 	presets.InputPresetName = inputPresetName
 	presets.SelectPlatform = selectPlatform
 	presets.SelectParameters = selectParameters
-	presets.BuildPresetCommand = func(preset *setup.Preset, config *setup.Config) *presets.CommandInfo {
+	presets.BuildPresetCommand = func(preset *setup.Preset, config *setup.Config) (*presets.CommandInfo, error) {
 		// Convert to main package types and call original function
 		mainPreset := (*Preset)(preset)
 		mainConfig := (*Config)(config)
-		cmdInfo := buildPresetCommand(mainPreset, mainConfig)
+		cmdInfo, err := buildPresetCommand(mainPreset, mainConfig)
+		if err != nil {
+			return nil, err
+		}
 		// Convert back to presets package type
 		return &presets.CommandInfo{
-			BaseCommand: cmdInfo.BaseCommand,
-			EnvVars:     cmdInfo.EnvVars,
-			WorkingDir:  cmdInfo.WorkingDir,
-		}
+			BaseCommand:   cmdInfo.BaseCommand,
+			Args:          cmdInfo.Args,
+			EnvVars:       cmdInfo.EnvVars,
+			SecretEnvVars: cmdInfo.SecretEnvVars,
+			WorkingDir:    cmdInfo.WorkingDir,
+		}, nil
 	}
 	presets.ExecuteCommand = func(cmdInfo *presets.CommandInfo) {
 		// Convert to main package type and call original function
 		mainCmdInfo := &CommandInfo{
-			BaseCommand: cmdInfo.BaseCommand,
-			EnvVars:     cmdInfo.EnvVars,
-			WorkingDir:  cmdInfo.WorkingDir,
+			BaseCommand:   cmdInfo.BaseCommand,
+			Args:          cmdInfo.Args,
+			EnvVars:       cmdInfo.EnvVars,
+			SecretEnvVars: cmdInfo.SecretEnvVars,
+			WorkingDir:    cmdInfo.WorkingDir,
 		}
 		executeCommand(mainCmdInfo)
 	}
@@ -125,19 +154,36 @@ func init() {
 	// Add the setup command
 	rootCmd.AddCommand(setup.SetupCmd)
 	
-	// Disable completion
+	// Completion scripts come from our own completion command, which adds an install helper
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
-	rootCmd.CompletionOptions.DisableNoDescFlag = true
-	rootCmd.CompletionOptions.DisableDescriptions = true
 }
 
-func main() {
-	// Pass config path to setup package
+// applyConfigSelection passes the config path and profile from the flags and the environment
+// to the setup package. A profile from the environment is checked like --profile, so a typo
+// does not silently create a new profile or escape the profiles directory.
+func applyConfigSelection() error {
 	if configPath != "" {
 		setup.SetConfigPath(configPath)
 	}
-	
-	if err := rootCmd.Execute(); err != nil {
+	if profileName == "" {
+		profileName = os.Getenv(setup.ProfileEnv)
+	}
+	if profileName != "" {
+		if err := setup.ValidateProfileName(profileName); err != nil {
+			return err
+		}
+		if !setup.ProfileExists(profileName) {
+			return fmt.Errorf("Profile '%s' does not exist, create it with 'ledger-live profile create %s'", profileName, profileName)
+		}
+		setup.SetProfile(profileName)
+	}
+	return nil
+}
+
+func main() {
+	err := rootCmd.Execute()
+	waitForUpdateRefresh()
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
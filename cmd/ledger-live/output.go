@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"ledger-live-starter/cmd/ledger-live/presets"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+// OutputFormat selects how list and show commands print their results
+type OutputFormat string
+
+const (
+	OutputTable OutputFormat = "table" // Styled output for people
+	OutputJSON  OutputFormat = "json"
+	OutputYAML  OutputFormat = "yaml"
+	OutputPlain OutputFormat = "plain" // Unstyled, tab separated lines for shell scripts
+)
+
+// Values of the output flags
+var (
+	outputFormat string
+	outputJSON   bool
+)
+
+// Errors, warnings and info messages go here: stdout, or stderr for the machine-readable
+// formats so stdout carries only the document
+var diagnostics io.Writer = os.Stdout
+
+// The structures below are the documented machine-readable output. Fields are only
+// ever added, so tools reading them keep working across versions.
+
+// PresetOutput describes a preset in `preset list` and `preset show`
+type PresetOutput struct {
+	Name                string            `json:"name" yaml:"name"`
+	ID                  string            `json:"id" yaml:"id"`
+	Extends             string            `json:"extends,omitempty" yaml:"extends,omitempty"`
+	Platform            string            `json:"platform" yaml:"platform"` // Empty when inherited
+	EffectivePlatform   string            `json:"effective_platform" yaml:"effective_platform"`
+	Parameters          []string          `json:"parameters" yaml:"parameters"`
+	RemoveParameters    []string          `json:"remove_parameters,omitempty" yaml:"remove_parameters,omitempty"`
+	EffectiveParameters []string          `json:"effective_parameters" yaml:"effective_parameters"`
+	Favorite            bool              `json:"favorite" yaml:"favorite"`
+	Group               string            `json:"group,omitempty" yaml:"group,omitempty"`
+	Note                string            `json:"note,omitempty" yaml:"note,omitempty"`
+	WorkDir             string            `json:"work_dir,omitempty" yaml:"work_dir,omitempty"`
+	Args                []string          `json:"args,omitempty" yaml:"args,omitempty"`
+	Env                 map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+// ParameterOutput describes a parameter in `param list`, with secret values masked
+type ParameterOutput struct {
+	Name        string   `json:"name" yaml:"name"`
+	ID          string   `json:"id" yaml:"id"`
+	EnvVar      string   `json:"env_var" yaml:"env_var"`
+	Description string   `json:"description" yaml:"description"`
+	Secret      bool     `json:"secret" yaml:"secret"`
+	Category    string   `json:"category,omitempty" yaml:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Platforms   []string `json:"platforms,omitempty" yaml:"platforms,omitempty"`
+	Requires    []string `json:"requires,omitempty" yaml:"requires,omitempty"`
+	Conflicts   []string `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+}
+
+// newPresetOutput converts a preset, resolving what it inherits. References are output
+// as names, the config stores them as IDs.
+func newPresetOutput(preset setup.Preset, config *setup.Config) PresetOutput {
+	names := func(ids []string) []string {
+		return emptyIfNil(setup.ParameterNames(ids, config.Parameters))
+	}
+	output := PresetOutput{
+		Name:                preset.Name,
+		ID:                  preset.ID,
+		Platform:            preset.Platform,
+		EffectivePlatform:   preset.Platform,
+		Parameters:          names(preset.Parameters),
+		RemoveParameters:    setup.ParameterNames(preset.RemoveParameters, config.Parameters),
+		EffectiveParameters: names(preset.Parameters),
+		Favorite:            preset.Favorite,
+		Group:               preset.Group,
+		Note:                preset.Note,
+		WorkDir:             preset.WorkDir,
+		Args:                preset.Args,
+		Env:                 preset.Env,
+	}
+	if preset.Extends != "" {
+		output.Extends = setup.PresetName(preset.Extends, config.Presets)
+	}
+	if effective, err := setup.ResolvePreset(preset, config.Presets); err == nil {
+		output.EffectivePlatform = effective.Platform
+		output.EffectiveParameters = names(effective.Parameters)
+	}
+	return output
+}
+
+// newParameterOutput converts a parameter, masking secret values and naming the
+// parameters it requires or conflicts with
+func newParameterOutput(param setup.Parameter, params []setup.Parameter) ParameterOutput {
+	return ParameterOutput{
+		Name:        param.Name,
+		ID:          param.ID,
+		EnvVar:      setup.MaskedEnvVar(param),
+		Description: param.Description,
+		Secret:      param.Secret,
+		Category:    param.Category,
+		Tags:        param.Tags,
+		Platforms:   param.Platforms,
+		Requires:    setup.ParameterNames(param.Requires, params),
+		Conflicts:   setup.ParameterNames(param.Conflicts, params),
+	}
+}
+
+// addOutputFlags registers --output and --json on a command
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&outputFormat, "output", string(OutputTable), "output format: table, json, yaml or plain")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "shorthand for --output json")
+	cmd.RegisterFlagCompletionFunc("output", completeValues(string(OutputTable), string(OutputJSON), string(OutputYAML), string(OutputPlain)))
+}
+
+// selectedOutputFormat returns the format chosen with the output flags, exiting on unknown ones.
+// For the machine-readable formats it moves diagnostics to stderr.
+func selectedOutputFormat() OutputFormat {
+	format := OutputFormat(strings.ToLower(outputFormat))
+	if outputJSON {
+		format = OutputJSON
+	}
+	switch format {
+	case OutputJSON, OutputYAML, OutputPlain:
+		diagnostics = os.Stderr
+		setup.Diagnostics = os.Stderr
+		presets.Diagnostics = os.Stderr
+		return format
+	case OutputTable:
+		return format
+	}
+	exitWithError(fmt.Errorf("unknown output format '%s' (expected table, json, yaml or plain)", outputFormat))
+	return ""
+}
+
+// printOutput prints data as JSON or YAML, or calls text for the table and plain formats
+func printOutput(format OutputFormat, data any, text func()) {
+	switch format {
+	case OutputJSON:
+		encoded, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Println(string(encoded))
+	case OutputYAML:
+		encoded, err := yaml.Marshal(data)
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Print(string(encoded))
+	default:
+		text()
+	}
+}
+
+// printTable prints rows as a styled table for the table format, or as tab separated lines for plain
+func printTable(format OutputFormat, headers []string, rows [][]string) {
+	if format == OutputPlain {
+		for _, row := range rows {
+			fmt.Println(strings.Join(row, "\t"))
+		}
+		return
+	}
+
+	fmt.Println(table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(palette.Primary)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if row == 0 { // Header row
+				return style.Bold(true)
+			}
+			return style
+		}).
+		Headers(headers...).
+		Rows(rows...).
+		Render())
+}
+
+// printFields prints key/value pairs as plain "key: value" lines, skipping empty values
+func printFields(fields [][2]string) {
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		fmt.Printf("%s: %s\n", field[0], field[1])
+	}
+}
+
+func emptyIfNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"ledger-live-starter/cmd/ledger-live/parameters"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+var paramCmd = &cobra.Command{
+	Use:     "param",
+	Aliases: []string{"params", "parameter", "parameters"},
+	Short:   "Manage parameters from the command line",
+	Long:    `Add, edit, remove and list parameters without the interactive menus.`,
+}
+
+// Values of the parameter field flags
+var (
+	paramEnvVar      string
+	paramDescription string
+	paramSecret      bool
+	paramSecretStdin bool
+	paramCategory    string
+	paramTags        string
+	paramPlatforms   []string
+	paramRequires    []string
+	paramConflicts   []string
+	paramRename      string
+	paramCascade     bool
+	paramReplace     string
+)
+
+var paramAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a parameter",
+	Long: `Add a parameter. Secret parameters take only the variable name in --env and read
+their value from the first line of stdin with --secret-stdin.`,
+	Example: `  ledger-live param add "Debug mode" --env DEBUG=1 --description "Verbose logs" --platform desktop
+  echo "$TOKEN" | ledger-live param add "API token" --secret --env API_TOKEN --secret-stdin`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfigOrExit()
+		param, secretValue, err := parameterFromFlags(cmd, setup.Parameter{Name: args[0]}, config)
+		if err != nil {
+			exitWithError(err)
+		}
+		if err := parameters.AddParameter(param, secretValue, config); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s %s '%s' %s\n", SuccessText("Success:"), NormalText("Parameter"), HighlightText(param.Name), NormalText("added successfully!"))
+	},
+}
+
+var paramEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Change fields of a parameter",
+	Long: `Change the fields given as flags and keep the others. Presets and rules refer to
+the parameter by ID, so they keep working after a rename. List flags such as --requires replace the whole list,
+pass them with an empty value to clear it.`,
+	Example:           `  ledger-live param edit "Debug mode" --rename "Verbose logs" --env VERBOSE=1`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeParameterNames),
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfigOrExit()
+		current := setup.FindParameter(args[0], config.Parameters)
+		if current == nil {
+			exitWithError(fmt.Errorf("parameter '%s' not found", args[0]))
+		}
+		param, secretValue, err := parameterFromFlags(cmd, *current, config)
+		if err != nil {
+			exitWithError(err)
+		}
+		if err := parameters.UpdateParameter(args[0], param, secretValue, config); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s %s '%s' %s\n", SuccessText("Success:"), NormalText("Parameter"), HighlightText(param.Name), NormalText("updated successfully!"))
+	},
+}
+
+var paramRemoveCmd = &cobra.Command{
+	Use:     "rm <name>...",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Remove parameters",
+	Long: `Remove parameters. Parameters still used by presets or other parameters' rules are
+only removed with --cascade, which drops those references, or --replace, which points
+them at another parameter.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: remainingArgs(completeParameterNames),
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfigOrExit()
+		if err := parameters.RemoveParameters(args, paramCascade, paramReplace, config); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText("Removed"), HighlightText(strings.Join(args, ", ")))
+	},
+}
+
+var paramListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List parameters",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := selectedOutputFormat()
+		config := loadConfigOrExit()
+
+		list := make([]ParameterOutput, 0, len(config.Parameters))
+		for _, param := range config.Parameters {
+			list = append(list, newParameterOutput(param, config.Parameters))
+		}
+		printOutput(format, list, func() {
+			var rows [][]string
+			for _, param := range list {
+				platforms := strings.Join(param.Platforms, ", ")
+				if platforms == "" {
+					platforms = "all"
+				}
+				rows = append(rows, []string{param.Name, param.EnvVar, platforms, param.Description})
+			}
+			printTable(format, []string{"Name", "Environment variable", "Platforms", "Description"}, rows)
+		})
+	},
+}
+
+// parameterFromFlags applies the parameter field flags that were set to a parameter,
+// returning the secret value read from stdin if requested. Parameter names given are
+// converted to IDs.
+func parameterFromFlags(cmd *cobra.Command, param setup.Parameter, config *setup.Config) (setup.Parameter, string, error) {
+	flags := cmd.Flags()
+	if flags.Changed("rename") {
+		param.Name = paramRename
+	}
+	if flags.Changed("env") {
+		param.EnvVar = paramEnvVar
+	}
+	if flags.Changed("description") {
+		param.Description = paramDescription
+	}
+	if flags.Changed("secret") {
+		param.Secret = paramSecret
+	}
+	if flags.Changed("category") {
+		param.Category = paramCategory
+	}
+	if flags.Changed("tags") {
+		param.Tags = parameters.ParseTags(paramTags)
+	}
+	if flags.Changed("platform") {
+		param.Platforms = nonEmpty(paramPlatforms)
+	}
+	if flags.Changed("requires") {
+		ids, err := setup.ParameterIDs(nonEmpty(paramRequires), config.Parameters)
+		if err != nil {
+			return param, "", err
+		}
+		param.Requires = ids
+	}
+	if flags.Changed("conflicts") {
+		ids, err := setup.ParameterIDs(nonEmpty(paramConflicts), config.Parameters)
+		if err != nil {
+			return param, "", err
+		}
+		param.Conflicts = ids
+	}
+
+	var secretValue string
+	if paramSecretStdin {
+		if !param.Secret {
+			return param, "", fmt.Errorf("--secret-stdin is only used for secret parameters")
+		}
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return param, "", fmt.Errorf("failed to read the secret value from stdin: %v", err)
+		}
+		secretValue = strings.TrimRight(line, "\r\n")
+	}
+	return param, secretValue, nil
+}
+
+// addParameterFieldFlags registers the flags shared by param add and edit
+func addParameterFieldFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&paramEnvVar, "env", "", "environment variable as NAME=value, or NAME for secrets")
+	cmd.Flags().StringVar(&paramDescription, "description", "", "description")
+	cmd.Flags().BoolVar(&paramSecret, "secret", false, "keep the value in the encrypted vault")
+	cmd.Flags().BoolVar(&paramSecretStdin, "secret-stdin", false, "read the secret value from stdin")
+	cmd.Flags().StringVar(&paramCategory, "category", "", "category shown in parameter selection")
+	cmd.Flags().StringVar(&paramTags, "tags", "", "comma separated search keywords")
+	cmd.Flags().StringArrayVar(&paramPlatforms, "platform", nil, "platform it applies to, repeat for several (default all)")
+	cmd.Flags().StringArrayVar(&paramRequires, "requires", nil, "parameter that must be selected too, repeat for several")
+	cmd.Flags().StringArrayVar(&paramConflicts, "conflicts", nil, "parameter that cannot be selected together, repeat for several")
+
+	cmd.RegisterFlagCompletionFunc("platform", completeValues(setup.Platforms...))
+	cmd.RegisterFlagCompletionFunc("requires", completeParameterNames)
+	cmd.RegisterFlagCompletionFunc("conflicts", completeParameterNames)
+}
+
+func init() {
+	addParameterFieldFlags(paramAddCmd)
+	paramCmd.AddCommand(paramAddCmd)
+
+	addParameterFieldFlags(paramEditCmd)
+	paramEditCmd.Flags().StringVar(&paramRename, "rename", "", "new parameter name")
+	paramCmd.AddCommand(paramEditCmd)
+
+	paramRemoveCmd.Flags().BoolVar(&paramCascade, "cascade", false, "remove references from presets and rules")
+	paramRemoveCmd.Flags().StringVar(&paramReplace, "replace", "", "point references at this parameter instead")
+	paramRemoveCmd.RegisterFlagCompletionFunc("replace", completeParameterNames)
+	paramCmd.AddCommand(paramRemoveCmd)
+
+	addOutputFlags(paramListCmd)
+	paramCmd.AddCommand(paramListCmd)
+	rootCmd.AddCommand(paramCmd)
+}
@@ -20,8 +20,20 @@ func AddNewParameter(config *setup.Config) {
 		return
 	}
 
-	// Get environment variable
-	envVar, err := getEnvironmentVariable("")
+	// Ask whether the value is a secret kept in the encrypted vault
+	secret, err := getParameterSecret()
+	if err != nil {
+		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Parameter kind input cancelled"))
+		return
+	}
+
+	// Get environment variable (secrets only store the variable name in the config)
+	var envVar, secretValue string
+	if secret {
+		envVar, secretValue, err = getSecretVariable()
+	} else {
+		envVar, err = getEnvironmentVariable("")
+	}
 	if err != nil {
 		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Environment variable input cancelled"))
 		return
@@ -36,9 +48,33 @@ func AddNewParameter(config *setup.Config) {
 
 	// Create new parameter
 	newParam := setup.Parameter{
+		ID:          setup.NewID(),
 		Name:        strings.TrimSpace(name),
 		EnvVar:      strings.TrimSpace(envVar),
 		Description: strings.TrimSpace(description),
+		Secret:      secret,
+	}
+
+	// Get category and tags
+	newParam.Category, newParam.Tags, err = getParameterCategoryAndTags("", nil)
+	if err != nil {
+		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Category input cancelled"))
+		return
+	}
+
+	// Get platform scoping, requirements and conflicts
+	newParam, err = getParameterRules(newParam, config.Parameters)
+	if err != nil {
+		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Parameter rules input cancelled"))
+		return
+	}
+
+	// Store the secret value before the config references it
+	if secret {
+		if err := setup.SetSecret(newParam, secretValue); err != nil {
+			fmt.Printf("%s %s %s\n", ErrorText("Error:"), NormalText("Error storing secret:"), NormalText(err.Error()))
+			return
+		}
 	}
 
 	config.Parameters = append(config.Parameters, newParam)
@@ -101,6 +137,59 @@ func getEnvironmentVariable(currentValue string) (string, error) {
 	return envVar, nil
 }
 
+// getParameterSecret asks whether the parameter holds a secret value
+func getParameterSecret() (bool, error) {
+	var secret bool
+
+	secretForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Is the value a secret (e.g. an API token)?").
+				Description("Secret values are stored encrypted and masked in all output.").
+				Value(&secret),
+		),
+	)
+
+	err := RunStyledForm(secretForm)
+	if err != nil {
+		return false, err
+	}
+
+	return secret, nil
+}
+
+// getSecretVariable gets the variable name and the hidden value of a secret parameter
+func getSecretVariable() (string, string, error) {
+	var name, value string
+
+	secretForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Enter environment variable name:").
+				Placeholder("e.g., 'API_TOKEN'").
+				Value(&name).
+				Validate(validateSecretVariableName),
+			huh.NewInput().
+				Title("Enter secret value:").
+				EchoMode(huh.EchoModePassword).
+				Value(&value).
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("secret value cannot be empty")
+					}
+					return nil
+				}),
+		),
+	)
+
+	err := RunStyledForm(secretForm)
+	if err != nil {
+		return "", "", err
+	}
+
+	return strings.TrimSpace(name), value, nil
+}
+
 // getParameterDescription gets parameter description (optional)
 func getParameterDescription(currentValue string) (string, error) {
 	var description string = currentValue
@@ -121,3 +210,73 @@ func getParameterDescription(currentValue string) (string, error) {
 
 	return description, nil
 }
+
+// getParameterCategoryAndTags gets the category and comma separated tags (both optional)
+func getParameterCategoryAndTags(currentCategory string, currentTags []string) (string, []string, error) {
+	var category string = currentCategory
+	var tags string = strings.Join(currentTags, ", ")
+
+	categoryForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Enter category:").
+				Placeholder("e.g., 'Onboarding', 'Networking'").
+				Value(&category),
+			huh.NewInput().
+				Title("Enter tags (comma separated):").
+				Placeholder("e.g., 'mobile, qa'").
+				Value(&tags),
+		),
+	)
+
+	err := RunStyledForm(categoryForm)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return strings.TrimSpace(category), ParseTags(tags), nil
+}
+
+// getParameterRules gets the platforms a parameter applies to and the parameters it requires or conflicts with
+func getParameterRules(param setup.Parameter, existingParams []setup.Parameter) (setup.Parameter, error) {
+	platforms := param.Platforms
+	requires := param.Requires
+	conflicts := param.Conflicts
+
+	rulesForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Platforms:").
+				Description("Leave empty to offer this parameter on all platforms.").
+				Options(createPlatformOptions(platforms)...).
+				Value(&platforms),
+			huh.NewMultiSelect[string]().
+				Title("Requires:").
+				Description("Parameters that are always selected together with this one.").
+				Options(createRelatedParameterOptions(existingParams, param.ID, requires)...).
+				Value(&requires),
+			huh.NewMultiSelect[string]().
+				Title("Conflicts with:").
+				Description("Parameters that cannot be selected together with this one.").
+				Options(createRelatedParameterOptions(existingParams, param.ID, conflicts)...).
+				Value(&conflicts).
+				Validate(func(names []string) error {
+					candidate := param
+					candidate.Platforms = platforms
+					candidate.Requires = requires
+					candidate.Conflicts = names
+					return setup.ValidateParameterRules(candidate, existingParams)
+				}),
+		),
+	)
+
+	err := RunStyledForm(rulesForm)
+	if err != nil {
+		return param, err
+	}
+
+	param.Platforms = platforms
+	param.Requires = requires
+	param.Conflicts = conflicts
+	return param, nil
+}
@@ -0,0 +1,135 @@
+package parameters
+
+import (
+	"fmt"
+	"strings"
+
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+// Non-interactive counterparts of the parameter forms, used by the `param` command.
+// They run the same validation as the forms and save the config.
+
+// AddParameter validates and adds a parameter. Secret parameters need their value.
+func AddParameter(param setup.Parameter, secretValue string, config *setup.Config) error {
+	param = trimParameter(param)
+	if param.ID == "" {
+		param.ID = setup.NewID()
+	}
+	if err := validateParameter(param, config.Parameters, ""); err != nil {
+		return err
+	}
+	if param.Secret {
+		if secretValue == "" {
+			return fmt.Errorf("secret value cannot be empty")
+		}
+		if err := setup.SetSecret(param, secretValue); err != nil {
+			return fmt.Errorf("error storing secret: %v", err)
+		}
+	}
+
+	config.Parameters = append(config.Parameters, param)
+	return setup.SaveConfig(config)
+}
+
+// UpdateParameter replaces a parameter. Presets and rules refer to it by ID, so they stay
+// attached when it is renamed. An empty secret value keeps the stored one.
+func UpdateParameter(currentName string, param setup.Parameter, secretValue string, config *setup.Config) error {
+	index, current := findParameterByName(currentName, config.Parameters)
+	if current == nil {
+		return fmt.Errorf("parameter '%s' not found", currentName)
+	}
+
+	param = trimParameter(param)
+	param.ID = current.ID
+	if err := validateParameter(param, config.Parameters, currentName); err != nil {
+		return err
+	}
+
+	previous := *current
+	switch {
+	case param.Secret && current.Secret:
+		if err := updateSecret(*current, param.Name, secretValue); err != nil {
+			return fmt.Errorf("error updating secret: %v", err)
+		}
+	case param.Secret:
+		if secretValue == "" {
+			return fmt.Errorf("secret value cannot be empty")
+		}
+		if err := setup.SetSecret(param, secretValue); err != nil {
+			return fmt.Errorf("error storing secret: %v", err)
+		}
+	}
+
+	config.Parameters[index] = param
+	if err := setup.SaveConfig(config); err != nil {
+		return err
+	}
+	// The stored value is only dropped once the parameter no longer uses it
+	if previous.Secret && !param.Secret {
+		deleteSecretValues([]setup.Parameter{previous})
+	}
+	return nil
+}
+
+// RemoveParameters deletes parameters. References from presets and rules are removed when
+// cascade is set or pointed at the replacement parameter, otherwise they abort the deletion.
+func RemoveParameters(paramNames []string, cascade bool, replacement string, config *setup.Config) error {
+	var ids []string
+	for _, name := range paramNames {
+		_, param := findParameterByName(name, config.Parameters)
+		if param == nil {
+			return fmt.Errorf("parameter '%s' not found", name)
+		}
+		ids = append(ids, param.ID)
+	}
+
+	refs := setup.FindParameterReferences(ids, config)
+	switch {
+	case refs.Empty():
+	case replacement != "":
+		_, param := findParameterByName(replacement, config.Parameters)
+		if param == nil || containsName(paramNames, replacement) {
+			return fmt.Errorf("replacement parameter '%s' not found", replacement)
+		}
+		setup.ReplaceParameterReferences(ids, param.ID, config)
+	case cascade:
+		setup.RemoveParameterReferences(ids, config)
+	default:
+		var users []string
+		users = append(users, refs.Presets...)
+		users = append(users, refs.Parameters...)
+		return fmt.Errorf("still used by %s (use --cascade or --replace)", strings.Join(users, ", "))
+	}
+
+	_, deletedSecrets := removeParameters(ids, config)
+	if err := setup.SaveConfig(config); err != nil {
+		return err
+	}
+	deleteSecretValues(deletedSecrets)
+	return nil
+}
+
+// validateParameter runs the checks of the parameter forms on a complete parameter
+func validateParameter(param setup.Parameter, existingParams []setup.Parameter, currentName string) error {
+	if err := validateParameterName(param.Name, existingParams, currentName); err != nil {
+		return err
+	}
+	if param.Secret {
+		if err := validateSecretVariableName(param.EnvVar); err != nil {
+			return err
+		}
+	} else if err := validateEnvironmentVariable(param.EnvVar); err != nil {
+		return err
+	}
+	return setup.ValidateParameterRules(param, existingParams)
+}
+
+// trimParameter trims the free text fields the way the forms do
+func trimParameter(param setup.Parameter) setup.Parameter {
+	param.Name = strings.TrimSpace(param.Name)
+	param.EnvVar = strings.TrimSpace(param.EnvVar)
+	param.Description = strings.TrimSpace(param.Description)
+	param.Category = strings.TrimSpace(param.Category)
+	return param
+}
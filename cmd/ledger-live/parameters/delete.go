@@ -2,6 +2,7 @@ package parameters
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"ledger-live-starter/cmd/ledger-live/setup"
@@ -90,35 +91,100 @@ func confirmParameterDeletion(paramNames []string, config *setup.Config) {
 	}
 
 	if confirm {
+		ids, err := setup.ParameterIDs(paramNames, config.Parameters)
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			ShowManagementMenu(config)
+			return
+		}
+		// Decide what happens to presets and rules still using the parameters
+		if !resolveParameterReferences(ids, config) {
+			fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Deletion cancelled."))
+			ShowManagementMenu(config)
+			return
+		}
 		// Delete the selected parameters
-		deleteMultipleParameters(paramNames, config)
+		deleteMultipleParameters(ids, config)
 	} else {
 		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Deletion cancelled."))
 		ShowManagementMenu(config)
 	}
 }
 
-// deleteMultipleParameters removes multiple parameters from config
-func deleteMultipleParameters(paramNames []string, config *setup.Config) {
-	// Create a set of parameter names for faster lookup
-	toDelete := make(map[string]bool)
-	for _, name := range paramNames {
-		toDelete[name] = true
+// resolveParameterReferences lists the presets and parameters referring to the parameters being
+// deleted and lets the user remove those references, point them at another parameter or abort.
+// It returns false if the deletion should not go ahead.
+func resolveParameterReferences(ids []string, config *setup.Config) bool {
+	refs := setup.FindParameterReferences(ids, config)
+	if refs.Empty() {
+		return true
 	}
 
-	// Filter out the parameters to delete
-	var remainingParameters []setup.Parameter
-	var deletedCount int
+	var lines []string
+	if len(refs.Presets) > 0 {
+		lines = append(lines, fmt.Sprintf("Used by presets: %s", strings.Join(refs.Presets, ", ")))
+	}
+	if len(refs.Parameters) > 0 {
+		lines = append(lines, fmt.Sprintf("Required by or conflicting with: %s", strings.Join(refs.Parameters, ", ")))
+	}
+
+	// Replacement candidates are the parameters that stay
+	var replacements []huh.Option[string]
 	for _, param := range config.Parameters {
-		if toDelete[param.Name] {
-			deletedCount++
-		} else {
-			remainingParameters = append(remainingParameters, param)
+		if !containsName(ids, param.ID) {
+			replacements = append(replacements, huh.NewOption(param.Name, param.ID))
 		}
 	}
 
-	// Update config
-	config.Parameters = remainingParameters
+	actions := []huh.Option[string]{huh.NewOption("Remove them from these presets and rules", "cascade")}
+	if len(replacements) > 0 {
+		actions = append(actions, huh.NewOption("Replace them with another parameter", "reassign"))
+	}
+	actions = append(actions, huh.NewOption("Abort", "abort"))
+
+	var action string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("These parameters are still in use:").
+				Description(strings.Join(lines, "\n")).
+				Options(actions...).
+				Value(&action),
+		),
+	)
+	if err := RunStyledForm(form); err != nil {
+		return false
+	}
+
+	switch action {
+	case "cascade":
+		setup.RemoveParameterReferences(ids, config)
+	case "reassign":
+		var replacement string
+		replacementForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title("Replace with:").
+					Options(replacements...).
+					Value(&replacement),
+			),
+		)
+		if err := RunStyledForm(replacementForm); err != nil {
+			return false
+		}
+		setup.ReplaceParameterReferences(ids, replacement, config)
+	default:
+		return false
+	}
+	return true
+}
+
+// deleteMultipleParameters removes multiple parameters from config
+func deleteMultipleParameters(ids []string, config *setup.Config) {
+	name := setup.ParameterName(ids[0], config.Parameters)
+
+	// Filter out the parameters to delete
+	deletedCount, deletedSecrets := removeParameters(ids, config)
 
 	// Save config
 	err := saveConfigWithError(config)
@@ -126,9 +192,12 @@ func deleteMultipleParameters(paramNames []string, config *setup.Config) {
 		return
 	}
 
+	// Remove the stored values of deleted secrets
+	deleteSecretValues(deletedSecrets)
+
 	// Show success message
 	if deletedCount == 1 {
-		fmt.Printf("%s %s '%s' %s\n", SuccessText("Success:"), NormalText("Parameter"), HighlightText(paramNames[0]), NormalText("deleted successfully."))
+		fmt.Printf("%s %s '%s' %s\n", SuccessText("Success:"), NormalText("Parameter"), HighlightText(name), NormalText("deleted successfully."))
 	} else {
 		fmt.Printf("%s %s %s\n", SuccessText("Success:"), HighlightText(fmt.Sprintf("%d", deletedCount)), NormalText("parameters deleted successfully."))
 	}
@@ -136,3 +205,33 @@ func deleteMultipleParameters(paramNames []string, config *setup.Config) {
 	// Return to management menu
 	ShowManagementMenu(config)
 }
+
+// removeParameters drops the parameters with the given IDs from the config, returning how
+// many were removed and the removed secret parameters
+func removeParameters(ids []string, config *setup.Config) (int, []setup.Parameter) {
+	var remainingParameters []setup.Parameter
+	var deletedSecrets []setup.Parameter
+	var deletedCount int
+	for _, param := range config.Parameters {
+		if containsName(ids, param.ID) {
+			deletedCount++
+			if param.Secret {
+				deletedSecrets = append(deletedSecrets, param)
+			}
+		} else {
+			remainingParameters = append(remainingParameters, param)
+		}
+	}
+	config.Parameters = remainingParameters
+	return deletedCount, deletedSecrets
+}
+
+// deleteSecretValues removes the stored values of deleted secrets, warning on failure
+func deleteSecretValues(params []setup.Parameter) {
+	if len(params) == 0 {
+		return
+	}
+	if err := setup.DeleteSecrets(params); err != nil {
+		fmt.Printf("%s %s %s\n", WarningText("Warning:"), NormalText("Could not remove secret values from the vault:"), NormalText(err.Error()))
+	}
+}
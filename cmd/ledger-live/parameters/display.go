@@ -2,6 +2,7 @@ package parameters
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"ledger-live-starter/cmd/ledger-live/setup"
@@ -56,10 +57,25 @@ func displayParametersList(parameters []setup.Parameter) {
 	for i, param := range parameters {
 		fmt.Printf("%s %s %d:\n", TitleText("•"), NormalText("Parameter"), i+1)
 		fmt.Printf("   %s %s\n", InfoTextTitle("Name:"), HighlightText(param.Name))
-		fmt.Printf("   %s %s\n", InfoTextTitle("Environment Variable:"), HighlightText(param.EnvVar))
+		fmt.Printf("   %s %s\n", InfoTextTitle("Environment Variable:"), HighlightText(setup.MaskedEnvVar(param)))
 		if param.Description != "" {
 			fmt.Printf("   %s %s\n", InfoTextTitle("Description:"), NormalText(param.Description))
 		}
+		if param.Category != "" {
+			fmt.Printf("   %s %s\n", InfoTextTitle("Category:"), NormalText(param.Category))
+		}
+		if len(param.Tags) > 0 {
+			fmt.Printf("   %s %s\n", InfoTextTitle("Tags:"), NormalText(strings.Join(param.Tags, ", ")))
+		}
+		if len(param.Platforms) > 0 {
+			fmt.Printf("   %s %s\n", InfoTextTitle("Platforms:"), NormalText(strings.Join(param.Platforms, ", ")))
+		}
+		if len(param.Requires) > 0 {
+			fmt.Printf("   %s %s\n", InfoTextTitle("Requires:"), NormalText(strings.Join(setup.ParameterNames(param.Requires, parameters), ", ")))
+		}
+		if len(param.Conflicts) > 0 {
+			fmt.Printf("   %s %s\n", InfoTextTitle("Conflicts with:"), NormalText(strings.Join(setup.ParameterNames(param.Conflicts, parameters), ", ")))
+		}
 		fmt.Println()
 	}
 }
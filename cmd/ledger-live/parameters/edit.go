@@ -58,7 +58,13 @@ func EditSingleParameter(paramName string, config *setup.Config) {
 	// Pre-fill with current values
 	name := currentParam.Name
 	envVar := currentParam.EnvVar
+	secretValue := ""
 	description := currentParam.Description
+	category := currentParam.Category
+	tags := strings.Join(currentParam.Tags, ", ")
+	platforms := currentParam.Platforms
+	requires := currentParam.Requires
+	conflicts := currentParam.Conflicts
 
 	// Create a single form with all fields pre-filled
 	form := huh.NewForm(
@@ -72,10 +78,41 @@ func EditSingleParameter(paramName string, config *setup.Config) {
 			huh.NewInput().
 				Title("Environment variable:").
 				Value(&envVar).
-				Validate(validateEnvironmentVariable),
+				Validate(func(s string) error {
+					if currentParam.Secret {
+						return validateSecretVariableName(s)
+					}
+					return validateEnvironmentVariable(s)
+				}),
 			huh.NewInput().
 				Title("Description:").
 				Value(&description),
+			huh.NewInput().
+				Title("Category:").
+				Value(&category),
+			huh.NewInput().
+				Title("Tags (comma separated):").
+				Value(&tags),
+			huh.NewMultiSelect[string]().
+				Title("Platforms:").
+				Description("Leave empty to offer this parameter on all platforms.").
+				Options(createPlatformOptions(platforms)...).
+				Value(&platforms),
+			huh.NewMultiSelect[string]().
+				Title("Requires:").
+				Options(createRelatedParameterOptions(config.Parameters, currentParam.ID, requires)...).
+				Value(&requires),
+			huh.NewMultiSelect[string]().
+				Title("Conflicts with:").
+				Options(createRelatedParameterOptions(config.Parameters, currentParam.ID, conflicts)...).
+				Value(&conflicts).
+				Validate(func(names []string) error {
+					candidate := *currentParam
+					candidate.Platforms = platforms
+					candidate.Requires = requires
+					candidate.Conflicts = names
+					return setup.ValidateParameterRules(candidate, config.Parameters)
+				}),
 		),
 	)
 
@@ -85,10 +122,39 @@ func EditSingleParameter(paramName string, config *setup.Config) {
 		return
 	}
 
-	// Update the parameter with new values
+	// Secrets can get a new value, which is never shown prefilled
+	if currentParam.Secret {
+		secretForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("New secret value (leave empty to keep):").
+					EchoMode(huh.EchoModePassword).
+					Value(&secretValue),
+			),
+		)
+		if err := RunStyledForm(secretForm); err != nil {
+			fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Parameter editing cancelled"))
+			return
+		}
+	}
+
+	// Update the secret value before the config changes
+	if currentParam.Secret {
+		if err := updateSecret(*currentParam, strings.TrimSpace(name), secretValue); err != nil {
+			fmt.Printf("%s %s %s\n", ErrorText("Error:"), NormalText("Error updating secret:"), NormalText(err.Error()))
+			return
+		}
+	}
+
+	// Update the parameter with new values, presets and rules refer to it by ID
 	config.Parameters[paramIndex].Name = strings.TrimSpace(name)
 	config.Parameters[paramIndex].EnvVar = strings.TrimSpace(envVar)
 	config.Parameters[paramIndex].Description = strings.TrimSpace(description)
+	config.Parameters[paramIndex].Category = strings.TrimSpace(category)
+	config.Parameters[paramIndex].Tags = ParseTags(tags)
+	config.Parameters[paramIndex].Platforms = platforms
+	config.Parameters[paramIndex].Requires = requires
+	config.Parameters[paramIndex].Conflicts = conflicts
 
 	// Save config
 	err = saveConfigWithError(config)
@@ -101,3 +167,17 @@ func EditSingleParameter(paramName string, config *setup.Config) {
 	// Return to parameter management menu
 	ShowManagementMenu(config)
 }
+
+// updateSecret optionally replaces the stored value of a secret parameter. A value stored
+// under the old name by versions before IDs is moved to the ID before a rename loses it.
+func updateSecret(current setup.Parameter, newName string, newValue string) error {
+	if newName != current.Name {
+		if err := setup.MigrateSecretKey(current); err != nil {
+			return err
+		}
+	}
+	if newValue != "" {
+		return setup.SetSecret(current, newValue)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package parameters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+// Category label used for parameters without a category
+const uncategorized = "Other"
+
+// pickerBindings groups the values the picker options depend on so huh recomputes them when any changes
+type pickerBindings struct {
+	Query    *string
+	Platform *string
+	Selected *[]string
+	Hovered  *string
+}
+
+// ParameterPicker is a multi-select of parameters that tracks the option under the cursor,
+// which huh does not expose, so the description can follow it
+type ParameterPicker struct {
+	*huh.MultiSelect[string]
+	options func() []huh.Option[string]
+	keymap  huh.MultiSelectKeyMap
+	cursor  int
+	focused bool
+	hovered *string
+}
+
+// NewParameterPicker creates a search input and a multi-select listing the parameters for a platform,
+// grouped by category and filtered by the search query. Options are valued by parameter ID. Selected
+// parameters always stay visible so a narrower search never drops them from the selection.
+func NewParameterPicker(params []setup.Parameter, platform *string, selected *[]string, title string) (*huh.Input, *ParameterPicker) {
+	var query, hovered string
+	bindings := &pickerBindings{Query: &query, Platform: platform, Selected: selected, Hovered: &hovered}
+
+	search := huh.NewInput().
+		Title("Search parameters:").
+		Placeholder("Filter by name, env var, description, category or tag").
+		Value(&query)
+
+	options := func() []huh.Option[string] {
+		return createGroupedParameterOptions(params, *platform, query, *selected)
+	}
+	picker := &ParameterPicker{options: options, keymap: huh.NewDefaultKeyMap().MultiSelect, hovered: &hovered}
+	picker.MultiSelect = huh.NewMultiSelect[string]().
+		Title(title).
+		DescriptionFunc(func() string {
+			if picker.focused && hovered != "" {
+				return describeParameter(params, hovered)
+			}
+			return describeSelection(params, query, *selected)
+		}, bindings).
+		OptionsFunc(options, bindings).
+		Filterable(false).
+		Value(selected)
+
+	return search, picker
+}
+
+// Validate sets the validation of the selection
+func (p *ParameterPicker) Validate(validate func([]string) error) *ParameterPicker {
+	p.MultiSelect.Validate(validate)
+	return p
+}
+
+// Focus focuses the picker, its description then follows the cursor
+func (p *ParameterPicker) Focus() tea.Cmd {
+	p.focused = true
+	return p.MultiSelect.Focus()
+}
+
+// Blur blurs the picker
+func (p *ParameterPicker) Blur() tea.Cmd {
+	p.focused = false
+	return p.MultiSelect.Blur()
+}
+
+// WithKeyMap sets the keymap, keeping the bindings that move the cursor
+func (p *ParameterPicker) WithKeyMap(k *huh.KeyMap) huh.Field {
+	p.keymap = k.MultiSelect
+	p.MultiSelect.WithKeyMap(k)
+	return p
+}
+
+// Update moves the tracked cursor the way huh moves its own. Half-page jumps depend on the
+// viewport huh keeps private and are ignored, like huh's own filter, which the search input replaces.
+func (p *ParameterPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	options := p.options()
+	if msg, ok := msg.(tea.KeyMsg); ok && p.focused {
+		switch {
+		case key.Matches(msg, p.keymap.Filter, p.keymap.HalfPageUp, p.keymap.HalfPageDown):
+			return p, nil
+		case key.Matches(msg, p.keymap.Up):
+			p.cursor--
+		case key.Matches(msg, p.keymap.Down):
+			p.cursor++
+		case key.Matches(msg, p.keymap.GotoTop):
+			p.cursor = 0
+		case key.Matches(msg, p.keymap.GotoBottom):
+			p.cursor = len(options) - 1
+		}
+	}
+	p.cursor = max(min(p.cursor, len(options)-1), 0)
+	*p.hovered = ""
+	if p.cursor < len(options) {
+		*p.hovered = options[p.cursor].Value
+	}
+
+	_, cmd := p.MultiSelect.Update(msg)
+	return p, cmd
+}
+
+// describeParameter returns the description of the parameter with the given ID
+func describeParameter(params []setup.Parameter, id string) string {
+	if param := setup.ParameterByID(id, params); param != nil {
+		return fmt.Sprintf("%s: %s", param.Name, parameterDescription(*param))
+	}
+	return ""
+}
+
+// describeSelection summarises the search and selection while the picker is not focused
+func describeSelection(params []setup.Parameter, query string, selected []string) string {
+	// A search narrowed down to one parameter shows that parameter
+	if strings.TrimSpace(query) != "" {
+		matches := 0
+		var match setup.Parameter
+		for _, param := range params {
+			if MatchesQuery(param, query) {
+				matches++
+				match = param
+			}
+		}
+		if matches == 1 {
+			return parameterDescription(match)
+		}
+		return fmt.Sprintf("%d parameters match '%s'", matches, strings.TrimSpace(query))
+	}
+
+	if len(selected) == 0 {
+		return "Select the parameters you want to use (type in the search field to filter)"
+	}
+	if len(selected) == 1 {
+		for _, param := range params {
+			if param.ID == selected[0] {
+				return parameterDescription(param)
+			}
+		}
+	}
+	return fmt.Sprintf("%d parameters selected", len(selected))
+}
+
+// parameterDescription returns the description line for a parameter
+func parameterDescription(param setup.Parameter) string {
+	if param.Description != "" {
+		return param.Description
+	}
+	return "- no description available -"
+}
+
+// createGroupedParameterOptions builds options grouped by category, keeping selected parameters visible
+func createGroupedParameterOptions(params []setup.Parameter, platform string, query string, selected []string) []huh.Option[string] {
+	var options []huh.Option[string]
+	for _, group := range GroupByCategory(setup.FilterParametersForPlatform(params, platform)) {
+		for _, param := range group.Parameters {
+			isSelected := containsName(selected, param.ID)
+			if !isSelected && !MatchesQuery(param, query) {
+				continue
+			}
+			label := fmt.Sprintf("%s %s", InfoTextTitle(group.Category+" ›"), param.Name)
+			option := huh.NewOption(label, param.ID)
+			if isSelected {
+				option = option.Selected(true)
+			}
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// CategoryGroup is a named set of parameters
+type CategoryGroup struct {
+	Category   string
+	Parameters []setup.Parameter
+}
+
+// GroupByCategory groups parameters by category in order of first appearance,
+// with uncategorized parameters last
+func GroupByCategory(params []setup.Parameter) []CategoryGroup {
+	var groups []CategoryGroup
+	index := make(map[string]int)
+	var other []setup.Parameter
+
+	for _, param := range params {
+		category := strings.TrimSpace(param.Category)
+		if category == "" {
+			other = append(other, param)
+			continue
+		}
+		i, ok := index[category]
+		if !ok {
+			i = len(groups)
+			index[category] = i
+			groups = append(groups, CategoryGroup{Category: category})
+		}
+		groups[i].Parameters = append(groups[i].Parameters, param)
+	}
+
+	if len(other) > 0 {
+		groups = append(groups, CategoryGroup{Category: uncategorized, Parameters: other})
+	}
+	return groups
+}
+
+// MatchesQuery reports whether a parameter matches a case-insensitive search
+// over its name, env var, description, category and tags
+func MatchesQuery(param setup.Parameter, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+
+	fields := []string{param.Name, setup.MaskedEnvVar(param), param.Description, param.Category}
+	fields = append(fields, param.Tags...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTags splits a comma separated tag list, dropping empty entries
+func ParseTags(input string) []string {
+	var tags []string
+	for _, tag := range strings.Split(input, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !containsName(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
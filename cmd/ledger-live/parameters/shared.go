@@ -63,6 +63,18 @@ func validateEnvironmentVariable(envVar string) error {
 	return nil
 }
 
+// validateSecretVariableName validates the variable name of a secret parameter
+func validateSecretVariableName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("variable name cannot be empty")
+	}
+	if strings.ContainsAny(name, "= \t") {
+		return fmt.Errorf("enter only the variable name (e.g., API_TOKEN), the value is asked separately")
+	}
+	return nil
+}
+
 // createParameterOptionsFromList creates huh options from parameter list
 func createParameterOptionsFromList(parameters []setup.Parameter) []huh.Option[string] {
 	var options []huh.Option[string]
@@ -72,6 +84,46 @@ func createParameterOptionsFromList(parameters []setup.Parameter) []huh.Option[s
 	return options
 }
 
+// createPlatformOptions creates huh options for all platforms with the current ones preselected
+func createPlatformOptions(selected []string) []huh.Option[string] {
+	var options []huh.Option[string]
+	for _, platform := range setup.Platforms {
+		option := huh.NewOption(setup.PlatformLabel(platform), platform)
+		if containsName(selected, platform) {
+			option = option.Selected(true)
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+// createRelatedParameterOptions creates huh options for every parameter except the one being edited,
+// valued by parameter ID
+func createRelatedParameterOptions(parameters []setup.Parameter, excludeID string, selected []string) []huh.Option[string] {
+	var options []huh.Option[string]
+	for _, param := range parameters {
+		if param.ID == excludeID {
+			continue
+		}
+		option := huh.NewOption(param.Name, param.ID)
+		if containsName(selected, param.ID) {
+			option = option.Selected(true)
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+// containsName checks whether a name is part of a list
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Text styling function placeholders - these will be injected from main
 var (
 	TitleText     func(text string) string
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"ledger-live-starter/cmd/ledger-live/presets"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+var presetCmd = &cobra.Command{
+	Use:     "preset",
+	Aliases: []string{"presets"},
+	Short:   "Manage and share presets from the command line",
+	Long: `Add, edit, remove, list and show presets without the interactive menus, and
+export presets to bundle files to share them with your team.`,
+}
+
+// Values of the preset field flags
+var (
+	presetPlatform     string
+	presetExtends      string
+	presetParams       []string
+	presetRemoveParams []string
+	presetGroup        string
+	presetNote         string
+	presetWorkDir      string
+	presetArgs         []string
+	presetEnv          []string
+	presetFavorite     bool
+	presetRename       string
+)
+
+var presetAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a preset",
+	Example: `  ledger-live preset add "Mobile QA" --platform mobile --param "Skip onboarding" --group QA
+  ledger-live preset add "Mobile QA (no broadcast)" --extends "Mobile QA" --param "Disable transaction broadcast"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfigOrExit()
+		preset, err := presetFromFlags(cmd, setup.Preset{Name: args[0]}, config)
+		if err != nil {
+			exitWithError(err)
+		}
+		addedNames, err := presets.AddPreset(preset, config)
+		if err != nil {
+			exitWithError(err)
+		}
+		printAddedRequiredParameters(addedNames)
+		fmt.Printf("%s %s '%s' %s\n", SuccessText("Success:"), NormalText("Preset"), HighlightText(preset.Name), NormalText("created successfully!"))
+	},
+}
+
+var presetEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Change fields of a preset",
+	Long: `Change the fields given as flags and keep the others. List flags such as --param
+replace the whole list, pass them with an empty value to clear it.`,
+	Example:           `  ledger-live preset edit "Mobile QA" --rename "Mobile release QA" --note "Used for release sign-off"`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completePresetNames),
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfigOrExit()
+		current := setup.FindPreset(args[0], config.Presets)
+		if current == nil {
+			exitWithError(fmt.Errorf("preset '%s' not found", args[0]))
+		}
+		preset, err := presetFromFlags(cmd, *current, config)
+		if err != nil {
+			exitWithError(err)
+		}
+		addedNames, err := presets.UpdatePreset(args[0], preset, config)
+		if err != nil {
+			exitWithError(err)
+		}
+		printAddedRequiredParameters(addedNames)
+		fmt.Printf("%s %s '%s' %s\n", SuccessText("Success:"), NormalText("Preset"), HighlightText(preset.Name), NormalText("updated successfully!"))
+	},
+}
+
+var presetRemoveCmd = &cobra.Command{
+	Use:               "rm <name>...",
+	Aliases:           []string{"remove", "delete"},
+	Short:             "Remove presets",
+	Long:              `Remove presets. Presets extending a removed preset keep their resolved settings.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: remainingArgs(completePresetNames),
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfigOrExit()
+		if err := presets.RemovePresets(args, config); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText("Removed"), HighlightText(strings.Join(args, ", ")))
+	},
+}
+
+var presetListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List presets",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := selectedOutputFormat()
+		config := loadConfigOrExit()
+
+		list := make([]PresetOutput, 0, len(config.Presets))
+		for _, preset := range config.Presets {
+			list = append(list, newPresetOutput(preset, config))
+		}
+		printOutput(format, list, func() {
+			var rows [][]string
+			for _, preset := range list {
+				rows = append(rows, []string{preset.Name, preset.EffectivePlatform, preset.Extends, preset.Group, strings.Join(preset.EffectiveParameters, ", ")})
+			}
+			printTable(format, []string{"Name", "Platform", "Extends", "Group", "Parameters"}, rows)
+		})
+	},
+}
+
+var presetShowCmd = &cobra.Command{
+	Use:               "show <name>",
+	Short:             "Show the settings of a preset",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completePresetNames),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := selectedOutputFormat()
+		config := loadConfigOrExit()
+		preset := setup.FindPreset(args[0], config.Presets)
+		if preset == nil {
+			exitWithError(fmt.Errorf("preset '%s' not found", args[0]))
+		}
+
+		output := newPresetOutput(*preset, config)
+		printOutput(format, output, func() {
+			if format == OutputPlain {
+				printFields([][2]string{
+					{"name", output.Name},
+					{"extends", output.Extends},
+					{"platform", output.EffectivePlatform},
+					{"parameters", strings.Join(output.EffectiveParameters, ", ")},
+					{"group", output.Group},
+					{"work_dir", output.WorkDir},
+					{"args", strings.Join(output.Args, " ")},
+					{"env", strings.ReplaceAll(setup.FormatEnvOverrides(output.Env), "\n", ", ")},
+					{"note", output.Note},
+				})
+				return
+			}
+			if err := presets.ShowPreset(args[0], config); err != nil {
+				exitWithError(err)
+			}
+		})
+	},
+}
+
+// presetFromFlags applies the preset field flags that were set to a preset, converting
+// the parameter and preset names given to IDs
+func presetFromFlags(cmd *cobra.Command, preset setup.Preset, config *setup.Config) (setup.Preset, error) {
+	flags := cmd.Flags()
+	if flags.Changed("rename") {
+		preset.Name = presetRename
+	}
+	if flags.Changed("platform") {
+		preset.Platform = strings.ToLower(presetPlatform)
+	}
+	if flags.Changed("extends") {
+		preset.Extends = ""
+		if presetExtends != "" {
+			base := setup.FindPreset(presetExtends, config.Presets)
+			if base == nil {
+				return preset, fmt.Errorf("base preset '%s' does not exist", presetExtends)
+			}
+			preset.Extends = base.ID
+		}
+	}
+	if flags.Changed("param") {
+		ids, err := setup.ParameterIDs(nonEmpty(presetParams), config.Parameters)
+		if err != nil {
+			return preset, err
+		}
+		preset.Parameters = ids
+	}
+	if flags.Changed("remove-param") {
+		ids, err := setup.ParameterIDs(nonEmpty(presetRemoveParams), config.Parameters)
+		if err != nil {
+			return preset, err
+		}
+		preset.RemoveParameters = ids
+	}
+	if flags.Changed("group") {
+		preset.Group = presetGroup
+	}
+	if flags.Changed("note") {
+		preset.Note = presetNote
+	}
+	if flags.Changed("workdir") {
+		preset.WorkDir = presetWorkDir
+	}
+	if flags.Changed("arg") {
+		preset.Args = nonEmpty(presetArgs)
+	}
+	if flags.Changed("env") {
+		env, err := setup.ParseEnvOverrides(strings.Join(presetEnv, "\n"))
+		if err != nil {
+			return preset, err
+		}
+		preset.Env = env
+	}
+	if flags.Changed("favorite") {
+		preset.Favorite = presetFavorite
+	}
+	return preset, nil
+}
+
+// addPresetFieldFlags registers the flags shared by preset add and edit
+func addPresetFieldFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&presetPlatform, "platform", "", "platform: mobile or desktop (inherited when extending)")
+	cmd.Flags().StringVar(&presetExtends, "extends", "", "base preset to inherit from")
+	cmd.Flags().StringArrayVar(&presetParams, "param", nil, "parameter name, repeat for several")
+	cmd.Flags().StringArrayVar(&presetRemoveParams, "remove-param", nil, "inherited parameter to drop, repeat for several")
+	cmd.Flags().StringVar(&presetGroup, "group", "", "start menu group")
+	cmd.Flags().StringVar(&presetNote, "note", "", "note shown in the start menu")
+	cmd.Flags().StringVar(&presetWorkDir, "workdir", "", "subdirectory of the Ledger Live path to run in")
+	cmd.Flags().StringArrayVar(&presetArgs, "arg", nil, "extra command argument, repeat for several")
+	cmd.Flags().StringArrayVar(&presetEnv, "env", nil, "env override as KEY=value, repeat for several")
+	cmd.Flags().BoolVar(&presetFavorite, "favorite", false, "pin to the top of the start menu")
+
+	cmd.RegisterFlagCompletionFunc("platform", completeValues(setup.Platforms...))
+	cmd.RegisterFlagCompletionFunc("extends", completePresetNames)
+	cmd.RegisterFlagCompletionFunc("param", completeParameterNames)
+	cmd.RegisterFlagCompletionFunc("remove-param", completeParameterNames)
+}
+
+// printAddedRequiredParameters reports parameters pulled in by requirements
+func printAddedRequiredParameters(names []string) {
+	if len(names) > 0 {
+		fmt.Printf("%s %s %s\n", InfoTextTitle("Info:"), NormalText("Added required parameters:"), HighlightText(strings.Join(names, ", ")))
+	}
+}
+
+var exportOutput string
+
+var presetExportCmd = &cobra.Command{
+	Use:   "export [names...]",
+	Short: "Export presets and the parameters they reference",
+	Long: `Write the named presets, or all presets if none are given, to a bundle file.
+Base presets they extend and every parameter they reference are included.
+Secret parameter values stay in your vault and are never exported.`,
+	Example:           `  ledger-live preset export "Debug" "Staging" -o team.json`,
+	ValidArgsFunction: remainingArgs(completePresetNames),
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfigOrExit()
+
+		bundle, err := presets.ExportBundle(config, args)
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			os.Exit(1)
+		}
+		if err := presets.WriteBundle(bundle, exportOutput); err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			os.Exit(1)
+		}
+		if exportOutput != "" && exportOutput != "-" {
+			fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText(fmt.Sprintf("Exported %d preset(s) and %d parameter(s) to", len(bundle.Presets), len(bundle.Parameters))), HighlightText(exportOutput))
+		}
+	},
+}
+
+var importStrategy string
+
+var presetImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a presets bundle",
+	Long: `Merge the presets and parameters of a bundle into your configuration.
+
+Items identical to yours are left alone. When a name is already taken by a
+different item you are asked whether to skip it, overwrite yours or import it
+under a new name. Use --strategy to apply the same answer to every collision.`,
+	Example: `  ledger-live preset import team.json
+  ledger-live preset import team.json --strategy rename`,
+	Args: cobra.ExactArgs(1),
+	Run:  runPresetImportCmd,
+}
+
+func runPresetImportCmd(cmd *cobra.Command, args []string) {
+	strategy, err := presets.ParseMergeStrategy(importStrategy)
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		os.Exit(1)
+	}
+
+	bundle, err := presets.ReadBundle(args[0])
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		os.Exit(1)
+	}
+
+	config := loadConfigOrExit()
+
+	summary, err := presets.ImportBundle(config, bundle, strategy)
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		os.Exit(1)
+	}
+	for _, problem := range setup.DanglingParameterReferences(config) {
+		fmt.Printf("%s %s\n", WarningText("Warning:"), NormalText(problem))
+	}
+
+	if len(summary.Added)+len(summary.Overwritten)+len(summary.Renamed) > 0 {
+		if err := setup.SaveConfig(config); err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			os.Exit(1)
+		}
+	}
+
+	printImportList("Added:", summary.Added)
+	printImportList("Overwritten:", summary.Overwritten)
+	printImportList("Renamed:", summary.Renamed)
+	printImportList("Skipped:", summary.Skipped)
+	printImportList("Unchanged:", summary.Unchanged)
+
+	for _, param := range bundle.Parameters {
+		if param.Secret {
+			fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Secret parameter values are not shared, set them with Edit parameters."))
+			break
+		}
+	}
+}
+
+// printImportList prints one section of the import summary
+func printImportList(title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("%s %s\n", TitleText(title), NormalText(strings.Join(items, ", ")))
+}
+
+func init() {
+	addPresetFieldFlags(presetAddCmd)
+	presetCmd.AddCommand(presetAddCmd)
+
+	addPresetFieldFlags(presetEditCmd)
+	presetEditCmd.Flags().StringVar(&presetRename, "rename", "", "new preset name")
+	presetCmd.AddCommand(presetEditCmd)
+
+	presetCmd.AddCommand(presetRemoveCmd)
+	addOutputFlags(presetListCmd)
+	presetCmd.AddCommand(presetListCmd)
+
+	addOutputFlags(presetShowCmd)
+	presetCmd.AddCommand(presetShowCmd)
+
+	presetExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "bundle file to write, stdout if omitted")
+	presetCmd.AddCommand(presetExportCmd)
+
+	presetImportCmd.Flags().StringVar(&importStrategy, "strategy", "", "resolve every collision with skip, overwrite or rename")
+	presetImportCmd.RegisterFlagCompletionFunc("strategy", completeValues("skip", "overwrite", "rename"))
+	presetCmd.AddCommand(presetImportCmd)
+	rootCmd.AddCommand(presetCmd)
+}
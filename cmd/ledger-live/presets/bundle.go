@@ -0,0 +1,412 @@
+package presets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+// Current bundle file format version. Version 1 bundles refer to parameters and presets
+// by name, later ones by ID.
+const bundleVersion = 2
+
+// Bundle is a shareable set of presets and the parameters they reference
+type Bundle struct {
+	BundleVersion int               `json:"bundle_version"`
+	Parameters    []setup.Parameter `json:"parameters"`
+	Presets       []setup.Preset    `json:"presets"`
+}
+
+// MergeStrategy decides what happens to an imported item whose name is already taken
+type MergeStrategy string
+
+const (
+	StrategyAsk       MergeStrategy = ""
+	StrategySkip      MergeStrategy = "skip"
+	StrategyOverwrite MergeStrategy = "overwrite"
+	StrategyRename    MergeStrategy = "rename"
+)
+
+// ParseMergeStrategy parses the value of the --strategy flag
+func ParseMergeStrategy(value string) (MergeStrategy, error) {
+	switch MergeStrategy(strings.ToLower(strings.TrimSpace(value))) {
+	case StrategyAsk:
+		return StrategyAsk, nil
+	case StrategySkip:
+		return StrategySkip, nil
+	case StrategyOverwrite:
+		return StrategyOverwrite, nil
+	case StrategyRename:
+		return StrategyRename, nil
+	}
+	return "", fmt.Errorf("unknown strategy '%s' (expected skip, overwrite or rename)", value)
+}
+
+// ImportSummary counts what an import did
+type ImportSummary struct {
+	Added       []string
+	Overwritten []string
+	Renamed     []string
+	Skipped     []string
+	Unchanged   []string
+}
+
+// ExportBundle builds a bundle with the named presets (all if none are given), the presets
+// they extend and every parameter they reference. Secret values are never exported.
+func ExportBundle(config *setup.Config, names []string) (*Bundle, error) {
+	if len(names) == 0 {
+		for _, preset := range config.Presets {
+			names = append(names, preset.Name)
+		}
+	}
+
+	bundle := &Bundle{BundleVersion: bundleVersion}
+	included := make(map[string]bool)
+
+	// Presets, including their bases so inheritance still resolves after import
+	var addPreset func(preset *setup.Preset) error
+	addPreset = func(preset *setup.Preset) error {
+		if included[preset.ID] {
+			return nil
+		}
+		included[preset.ID] = true
+		if preset.Extends != "" {
+			base := setup.PresetByID(preset.Extends, config.Presets)
+			if base == nil {
+				return fmt.Errorf("preset '%s' extends unknown preset '%s'", preset.Name, preset.Extends)
+			}
+			if err := addPreset(base); err != nil {
+				return err
+			}
+		}
+		bundle.Presets = append(bundle.Presets, *preset)
+		return nil
+	}
+	for _, name := range names {
+		preset := setup.FindPreset(name, config.Presets)
+		if preset == nil {
+			return nil, fmt.Errorf("preset '%s' not found", name)
+		}
+		if err := addPreset(preset); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parameters used by the presets, plus those their rules refer to
+	var queue []string
+	for _, preset := range bundle.Presets {
+		queue = append(queue, preset.Parameters...)
+		queue = append(queue, preset.RemoveParameters...)
+	}
+	seen := make(map[string]bool)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		param := setup.ParameterByID(id, config.Parameters)
+		if param == nil {
+			return nil, fmt.Errorf("parameter '%s' not found", id)
+		}
+		bundle.Parameters = append(bundle.Parameters, *param)
+		queue = append(queue, param.Requires...)
+		queue = append(queue, param.Conflicts...)
+	}
+
+	return bundle, nil
+}
+
+// WriteBundle writes a bundle as JSON to a file, or to stdout for "" or "-"
+func WriteBundle(bundle *Bundle, path string) error {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+	if path == "" || path == "-" {
+		fmt.Println(string(data))
+		return nil
+	}
+	return setup.WriteFileAtomic(path, append(data, '\n'), 0644)
+}
+
+// ReadBundle reads a bundle file, converting references written as names to IDs
+func ReadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle %s: %v", path, err)
+	}
+	if bundle.BundleVersion > bundleVersion {
+		return nil, fmt.Errorf("bundle %s was written by a newer version of ledger-live", path)
+	}
+	contents := &setup.Config{Parameters: bundle.Parameters, Presets: bundle.Presets}
+	setup.ResolveReferenceNames(contents)
+	bundle.Parameters, bundle.Presets = contents.Parameters, contents.Presets
+	return &bundle, nil
+}
+
+// ImportBundle merges a bundle into the config. Items are matched by name; items whose
+// name is taken and whose content differs are resolved with the strategy, or by asking
+// when it is StrategyAsk. The config is not saved.
+func ImportBundle(config *setup.Config, bundle *Bundle, strategy MergeStrategy) (*ImportSummary, error) {
+	summary := &ImportSummary{}
+
+	// Bundle IDs -> IDs in the config, so imported references point at the right entries
+	paramIDs := make(map[string]string)
+	presetIDs := make(map[string]string)
+	var importedParams, importedPresets []string
+
+	for _, param := range bundle.Parameters {
+		bundleID := param.ID
+		existing := setup.FindParameter(param.Name, config.Parameters)
+		if existing == nil {
+			warnSharedVariable(param, config.Parameters)
+			param.ID = uniqueParameterID(param.ID, config.Parameters)
+			config.Parameters = append(config.Parameters, param)
+			paramIDs[bundleID] = param.ID
+			importedParams = append(importedParams, param.ID)
+			summary.Added = append(summary.Added, "parameter "+param.Name)
+			continue
+		}
+		paramIDs[bundleID] = existing.ID
+		if sameParameter(*existing, config.Parameters, param, bundle.Parameters) {
+			summary.Unchanged = append(summary.Unchanged, "parameter "+param.Name)
+			continue
+		}
+
+		action, err := resolveCollision("Parameter", param.Name, describeParameterChange(*existing, param), strategy)
+		if err != nil {
+			return nil, err
+		}
+		switch action {
+		case StrategyOverwrite:
+			param.ID = existing.ID
+			*existing = param
+			importedParams = append(importedParams, param.ID)
+			summary.Overwritten = append(summary.Overwritten, "parameter "+param.Name)
+		case StrategyRename:
+			oldName := param.Name
+			param.Name = uniqueName(param.Name, func(name string) bool { return setup.FindParameter(name, config.Parameters) != nil })
+			param.ID = uniqueParameterID("", config.Parameters)
+			config.Parameters = append(config.Parameters, param)
+			paramIDs[bundleID] = param.ID
+			importedParams = append(importedParams, param.ID)
+			summary.Renamed = append(summary.Renamed, fmt.Sprintf("parameter %s -> %s", oldName, param.Name))
+		default:
+			summary.Skipped = append(summary.Skipped, "parameter "+param.Name)
+		}
+	}
+
+	for _, preset := range bundle.Presets {
+		bundleID := preset.ID
+		index := findPresetIndex(preset.Name, config.Presets)
+		if index < 0 {
+			preset.ID = uniquePresetID(preset.ID, config.Presets)
+			config.Presets = append(config.Presets, preset)
+			presetIDs[bundleID] = preset.ID
+			importedPresets = append(importedPresets, preset.ID)
+			summary.Added = append(summary.Added, "preset "+preset.Name)
+			continue
+		}
+		existing := config.Presets[index]
+		presetIDs[bundleID] = existing.ID
+		if samePreset(existing, config, preset, bundle) {
+			summary.Unchanged = append(summary.Unchanged, "preset "+preset.Name)
+			continue
+		}
+
+		action, err := resolveCollision("Preset", preset.Name, describePresetChange(existing, config, preset, bundle), strategy)
+		if err != nil {
+			return nil, err
+		}
+		switch action {
+		case StrategyOverwrite:
+			preset.ID = existing.ID
+			config.Presets[index] = preset
+			importedPresets = append(importedPresets, preset.ID)
+			summary.Overwritten = append(summary.Overwritten, "preset "+preset.Name)
+		case StrategyRename:
+			oldName := preset.Name
+			preset.Name = uniqueName(preset.Name, func(name string) bool { return findPresetIndex(name, config.Presets) >= 0 })
+			preset.ID = uniquePresetID("", config.Presets)
+			config.Presets = append(config.Presets, preset)
+			presetIDs[bundleID] = preset.ID
+			importedPresets = append(importedPresets, preset.ID)
+			summary.Renamed = append(summary.Renamed, fmt.Sprintf("preset %s -> %s", oldName, preset.Name))
+		default:
+			summary.Skipped = append(summary.Skipped, "preset "+preset.Name)
+		}
+	}
+
+	// Point the references of imported items at the entries they now correspond to
+	for _, id := range importedParams {
+		param := setup.ParameterByID(id, config.Parameters)
+		param.Requires = mapIDs(param.Requires, paramIDs)
+		param.Conflicts = mapIDs(param.Conflicts, paramIDs)
+	}
+	for _, id := range importedPresets {
+		preset := setup.PresetByID(id, config.Presets)
+		preset.Parameters = mapIDs(preset.Parameters, paramIDs)
+		preset.RemoveParameters = mapIDs(preset.RemoveParameters, paramIDs)
+		if mapped, ok := presetIDs[preset.Extends]; ok {
+			preset.Extends = mapped
+		}
+	}
+
+	return summary, nil
+}
+
+// resolveCollision returns the action for a name collision, asking unless a strategy is set
+func resolveCollision(kind string, name string, difference string, strategy MergeStrategy) (MergeStrategy, error) {
+	if strategy != StrategyAsk {
+		return strategy, nil
+	}
+
+	action := StrategySkip
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[MergeStrategy]().
+				Title(fmt.Sprintf("%s '%s' already exists with different settings", kind, name)).
+				Description(difference).
+				Options(
+					huh.NewOption("Skip (keep mine)", StrategySkip),
+					huh.NewOption("Overwrite with the imported version", StrategyOverwrite),
+					huh.NewOption("Import under a new name", StrategyRename),
+				).
+				Value(&action),
+		),
+	)
+	if err := RunStyledForm(form); err != nil {
+		return "", fmt.Errorf("import cancelled")
+	}
+	return action, nil
+}
+
+// describeParameterChange summarizes how an imported parameter differs from the existing one
+func describeParameterChange(existing setup.Parameter, imported setup.Parameter) string {
+	var lines []string
+	if setup.MaskedEnvVar(existing) != setup.MaskedEnvVar(imported) {
+		lines = append(lines, fmt.Sprintf("env: %s → %s", setup.MaskedEnvVar(existing), setup.MaskedEnvVar(imported)))
+	}
+	if existing.Description != imported.Description {
+		lines = append(lines, fmt.Sprintf("description: %q → %q", existing.Description, imported.Description))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "Rules, category or tags differ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describePresetChange summarizes how an imported preset differs from the existing one
+func describePresetChange(existing setup.Preset, config *setup.Config, imported setup.Preset, bundle *Bundle) string {
+	var lines []string
+	if existing.Platform != imported.Platform {
+		lines = append(lines, fmt.Sprintf("platform: %s → %s", existing.Platform, imported.Platform))
+	}
+	existingParams := setup.ParameterNames(existing.Parameters, config.Parameters)
+	importedParams := setup.ParameterNames(imported.Parameters, bundle.Parameters)
+	if !reflect.DeepEqual(existingParams, importedParams) {
+		lines = append(lines, fmt.Sprintf("parameters: %s → %s", strings.Join(existingParams, ", "), strings.Join(importedParams, ", ")))
+	}
+	if !reflect.DeepEqual(existing.Env, imported.Env) {
+		lines = append(lines, "env overrides differ")
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "Other settings differ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// warnSharedVariable points out a new parameter setting a variable an existing parameter already sets
+func warnSharedVariable(param setup.Parameter, params []setup.Parameter) {
+	name := setup.SecretEnvVarName(param)
+	for _, existing := range params {
+		if setup.SecretEnvVarName(existing) == name && setup.MaskedEnvVar(existing) != setup.MaskedEnvVar(param) {
+			fmt.Printf("%s %s\n", WarningText("Warning:"), NormalText(fmt.Sprintf("Imported parameter '%s' sets %s, which '%s' sets to a different value", param.Name, setup.MaskedEnvVar(param), existing.Name)))
+			return
+		}
+	}
+}
+
+// sameParameter compares parameters ignoring their IDs, comparing references by name
+func sameParameter(a setup.Parameter, aParams []setup.Parameter, b setup.Parameter, bParams []setup.Parameter) bool {
+	a.ID, b.ID = "", ""
+	a.Requires, b.Requires = setup.ParameterNames(a.Requires, aParams), setup.ParameterNames(b.Requires, bParams)
+	a.Conflicts, b.Conflicts = setup.ParameterNames(a.Conflicts, aParams), setup.ParameterNames(b.Conflicts, bParams)
+	return reflect.DeepEqual(a, b)
+}
+
+// samePreset compares presets ignoring their IDs and local usage settings, comparing
+// references by name
+func samePreset(a setup.Preset, config *setup.Config, b setup.Preset, bundle *Bundle) bool {
+	a.ID, b.ID = "", ""
+	a.Favorite, b.Favorite = false, false
+	a.Parameters, b.Parameters = setup.ParameterNames(a.Parameters, config.Parameters), setup.ParameterNames(b.Parameters, bundle.Parameters)
+	a.RemoveParameters, b.RemoveParameters = setup.ParameterNames(a.RemoveParameters, config.Parameters), setup.ParameterNames(b.RemoveParameters, bundle.Parameters)
+	if a.Extends != "" {
+		a.Extends = setup.PresetName(a.Extends, config.Presets)
+	}
+	if b.Extends != "" {
+		b.Extends = setup.PresetName(b.Extends, bundle.Presets)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// uniqueName appends " (imported)", then a number, until the name is free
+func uniqueName(name string, taken func(string) bool) string {
+	candidate := name + " (imported)"
+	for i := 2; taken(candidate); i++ {
+		candidate = fmt.Sprintf("%s (imported %d)", name, i)
+	}
+	return candidate
+}
+
+// uniqueParameterID keeps an imported ID unless it is already in use
+func uniqueParameterID(id string, params []setup.Parameter) string {
+	if id == "" || setup.FindParameterByID(id, params) >= 0 {
+		return setup.NewID()
+	}
+	return id
+}
+
+// uniquePresetID keeps an imported ID unless it is already in use
+func uniquePresetID(id string, presets []setup.Preset) string {
+	if id == "" || setup.FindPresetByID(id, presets) >= 0 {
+		return setup.NewID()
+	}
+	return id
+}
+
+// mapIDs replaces the IDs found in the mapping, keeping the others
+func mapIDs(ids []string, mapping map[string]string) []string {
+	if ids == nil {
+		return nil
+	}
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		if mapped, ok := mapping[id]; ok {
+			id = mapped
+		}
+		result[i] = id
+	}
+	return result
+}
+
+func findPresetIndex(name string, presets []setup.Preset) int {
+	for i := range presets {
+		if presets[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,125 @@
+package presets
+
+import (
+	"fmt"
+	"strings"
+
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+// Non-interactive counterparts of the preset forms, used by the `preset` command.
+// They run the same validation as the forms and save the config.
+
+// AddPreset validates and adds a preset. Parameters required by the selection are added
+// as local parameters and their names returned.
+func AddPreset(preset setup.Preset, config *setup.Config) ([]string, error) {
+	preset = trimPreset(preset)
+	if preset.ID == "" {
+		preset.ID = setup.NewID()
+	}
+	addedNames, err := validatePreset(&preset, config.Presets, config.Parameters, "")
+	if err != nil {
+		return nil, err
+	}
+
+	config.Presets = append(config.Presets, preset)
+	return addedNames, setup.SaveConfig(config)
+}
+
+// UpdatePreset replaces a preset. Presets extending it and its usage refer to it by ID, so
+// they stay attached when it is renamed.
+func UpdatePreset(currentName string, preset setup.Preset, config *setup.Config) ([]string, error) {
+	index, current := findPresetByName(currentName, config.Presets)
+	if current == nil {
+		return nil, fmt.Errorf("preset '%s' not found", currentName)
+	}
+
+	preset = trimPreset(preset)
+	preset.ID = current.ID
+	addedNames, err := validatePreset(&preset, config.Presets, config.Parameters, currentName)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Presets[index] = preset
+	return addedNames, setup.SaveConfig(config)
+}
+
+// RemovePresets deletes presets, detaching presets that extend them
+func RemovePresets(presetNames []string, config *setup.Config) error {
+	for _, name := range presetNames {
+		if _, preset := findPresetByName(name, config.Presets); preset == nil {
+			return fmt.Errorf("preset '%s' not found", name)
+		}
+	}
+
+	removePresets(presetNames, config)
+	return setup.SaveConfig(config)
+}
+
+// validatePreset runs the checks of the preset forms on a complete preset and adds the
+// parameters its selection requires, returning their names
+func validatePreset(preset *setup.Preset, presets []setup.Preset, params []setup.Parameter, currentName string) ([]string, error) {
+	if err := ValidatePresetName(preset.Name, presets, currentName); err != nil {
+		return nil, err
+	}
+	if preset.Extends == "" && preset.Platform == "" {
+		return nil, fmt.Errorf("a platform is required for presets that do not extend another preset")
+	}
+	if preset.Platform != "" && !containsPresetName(setup.Platforms, preset.Platform) {
+		return nil, fmt.Errorf("unknown platform '%s' (expected %s)", preset.Platform, strings.Join(setup.Platforms, " or "))
+	}
+	if err := setup.ValidateWorkDir(preset.WorkDir); err != nil {
+		return nil, err
+	}
+
+	// Check inheritance against the other presets, as this one may be renamed
+	var others []setup.Preset
+	for _, p := range presets {
+		if p.ID != preset.ID {
+			others = append(others, p)
+		}
+	}
+	if err := setup.ValidateExtends(*preset, others); err != nil {
+		return nil, err
+	}
+
+	effectivePreset, err := setup.ResolvePreset(*preset, others)
+	if err != nil {
+		return nil, err
+	}
+	_, addedIDs, err := setup.ResolveParameterSelection(effectivePreset.Parameters, params, effectivePreset.Platform)
+	if err != nil {
+		return nil, err
+	}
+	preset.Parameters = append(preset.Parameters, addedIDs...)
+	return setup.ParameterNames(addedIDs, params), nil
+}
+
+// trimPreset trims the free text fields the way the forms do
+func trimPreset(preset setup.Preset) setup.Preset {
+	preset.Name = strings.TrimSpace(preset.Name)
+	preset.Note = strings.TrimSpace(preset.Note)
+	preset.WorkDir = strings.TrimSpace(preset.WorkDir)
+	preset.Group = strings.TrimSpace(preset.Group)
+	return preset
+}
+
+// ShowPreset prints a preset summary followed by its effective parameters
+func ShowPreset(name string, config *setup.Config) error {
+	_, preset := findPresetByName(name, config.Presets)
+	if preset == nil {
+		return fmt.Errorf("preset '%s' not found", name)
+	}
+	displayPresetSummary(*preset, config)
+
+	if preset.Extends != "" {
+		effective, err := setup.ResolvePreset(*preset, config.Presets)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("   %s %s\n", InfoTextTitle("Effective platform:"), HighlightText(setup.PlatformLabel(effective.Platform)))
+		fmt.Printf("   %s %s\n\n", InfoTextTitle("Effective parameters:"), NormalText(strings.Join(setup.ParameterNames(effective.Parameters, config.Parameters), ", ")))
+	}
+	return nil
+}
@@ -43,34 +43,59 @@ func createPresetCore(config *setup.Config) (*setup.Preset, error) {
 		return nil, err
 	}
 
-	// Step 2: Platform selection
-	_, platformCommand, err := SelectPlatform()
+	// Step 2: Optional base preset to inherit from
+	basePreset, err := selectBasePreset(config.Presets)
 	if err != nil {
 		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
 		return nil, err
 	}
 
-	// Convert platform name to platform key
-	platformKey := convertPlatformToKey(platformCommand)
+	// Step 3: Platform selection (inherited when extending a preset)
+	var platformKey, effectivePlatform string
+	if basePreset != nil {
+		effectivePlatform = basePreset.Platform
+		fmt.Printf("%s %s %s\n", InfoTextTitle("Info:"), NormalText("Inheriting platform and parameters from"), HighlightText(basePreset.Name))
+	} else {
+		_, platformCommand, err := SelectPlatform()
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			return nil, err
+		}
+
+		// Convert platform name to platform key
+		platformKey = setup.PlatformKey(platformCommand)
+		effectivePlatform = platformKey
+	}
 
-	// Step 3: Parameter selection
-	selectedParams, err := SelectParameters(config.Parameters)
+	// Step 4: Parameter selection
+	selectedParams, err := SelectParameters(config.Parameters, effectivePlatform)
 	if err != nil {
 		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
 		return nil, err
 	}
 
-	// Convert selected parameters to parameter names
-	parameterNames := extractParameterNames(selectedParams)
-
-	// Step 4: Create new preset
+	// Step 5: Create new preset
 	newPreset := setup.Preset{
+		ID:         setup.NewID(),
 		Name:       presetName,
 		Platform:   platformKey,
-		Parameters: parameterNames,
+		Parameters: extractParameterIDs(selectedParams),
+	}
+	if basePreset != nil {
+		newPreset.Extends = basePreset.ID
 	}
 
-	// Step 5: Save to config
+	// Inherited and local parameters must work together
+	effectivePreset, err := setup.ResolvePreset(newPreset, config.Presets)
+	if err == nil {
+		err = setup.ValidatePreset(effectivePreset, config.Parameters)
+	}
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		return nil, err
+	}
+
+	// Step 6: Save to config
 	config.Presets = append(config.Presets, newPreset)
 	
 	err = setup.SaveConfig(config)
@@ -82,7 +107,7 @@ func createPresetCore(config *setup.Config) (*setup.Preset, error) {
 	fmt.Printf("%s %s '%s' %s\n\n", SuccessText("Success:"), NormalText("Preset"), HighlightText(presetName), NormalText("created successfully!"))
 	
 	// Show preset summary
-	displayPresetSummary(newPreset)
+	displayPresetSummary(newPreset, config)
 	
 	return &newPreset, nil
 }
@@ -115,7 +140,12 @@ func showPostCreationMenu(createdPreset setup.Preset, config *setup.Config) {
 	case "run":
 		// Execute the newly created preset
 		fmt.Printf("%s %s %s\n", SuccessText("✓"), NormalText("Starting preset:"), HighlightText(createdPreset.Name))
-		cmdInfo := BuildPresetCommand(&createdPreset, config)
+		cmdInfo, err := BuildPresetCommand(&createdPreset, config)
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			return
+		}
+		recordPresetRun(createdPreset)
 		ExecuteCommand(cmdInfo)
 	case "add":
 		// Create another preset
@@ -158,7 +188,12 @@ func showManagementPostCreationMenu(createdPreset setup.Preset, config *setup.Co
 	case "run":
 		// Execute the newly created preset
 		fmt.Printf("%s %s %s\n", SuccessText("✓"), NormalText("Starting preset:"), HighlightText(createdPreset.Name))
-		cmdInfo := BuildPresetCommand(&createdPreset, config)
+		cmdInfo, err := BuildPresetCommand(&createdPreset, config)
+		if err != nil {
+			fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+			return
+		}
+		recordPresetRun(createdPreset)
 		ExecuteCommand(cmdInfo)
 	case "add":
 		// Create another preset
@@ -173,3 +208,41 @@ func showManagementPostCreationMenu(createdPreset setup.Preset, config *setup.Co
 		ShowManagementMenu(newConfig)
 	}
 }
+
+// selectBasePreset lets the user pick a preset to inherit from, returning its effective settings
+func selectBasePreset(presets []setup.Preset) (*setup.Preset, error) {
+	if len(presets) == 0 {
+		return nil, nil
+	}
+
+	var selected string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Extend an existing preset?").
+				Description("The new preset inherits the platform and parameters of the chosen preset.").
+				Options(createBasePresetOptions(presets, "")...).
+				Value(&selected),
+		),
+	)
+
+	err := RunStyledForm(form)
+	if err != nil {
+		return nil, fmt.Errorf("base preset selection cancelled")
+	}
+
+	if selected == "" {
+		return nil, nil
+	}
+
+	base := setup.FindPreset(selected, presets)
+	if base == nil {
+		return nil, fmt.Errorf("preset '%s' not found", selected)
+	}
+	resolved, err := setup.ResolvePreset(*base, presets)
+	if err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
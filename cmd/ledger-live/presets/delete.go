@@ -2,6 +2,7 @@ package presets
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"ledger-live-starter/cmd/ledger-live/setup"
@@ -57,11 +58,29 @@ func confirmDeletion(presetNames []string, config *setup.Config) {
 	if len(presetNames) == 1 {
 		message = fmt.Sprintf("Are you sure you want to delete '%s'?", presetNames[0])
 	}
+
+	// Warn about presets that extend the ones being deleted
+	description := ""
+	for _, name := range presetNames {
+		var remainingChildren []string
+		for _, child := range setup.PresetChildren(setup.FindPreset(name, config.Presets).ID, config.Presets) {
+			if !containsPresetName(presetNames, child) {
+				remainingChildren = append(remainingChildren, child)
+			}
+		}
+		if len(remainingChildren) > 0 {
+			description += fmt.Sprintf("⚠ '%s' is extended by %s. ", name, strings.Join(remainingChildren, ", "))
+		}
+	}
+	if description != "" {
+		description += "They will keep their current settings but no longer inherit."
+	}
 	
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
 				Title(message).
+				Description(description).
 				Affirmative("Delete").
 				Negative("Cancel").
 				Value(&confirm),
@@ -86,25 +105,8 @@ func confirmDeletion(presetNames []string, config *setup.Config) {
 
 // deleteMultiplePresets removes multiple presets from config
 func deleteMultiplePresets(presetNames []string, config *setup.Config) {
-	// Create a set of preset names for faster lookup
-	toDelete := make(map[string]bool)
-	for _, name := range presetNames {
-		toDelete[name] = true
-	}
-
 	// Filter out the presets to delete
-	var remainingPresets []setup.Preset
-	var deletedCount int
-	for _, preset := range config.Presets {
-		if toDelete[preset.Name] {
-			deletedCount++
-		} else {
-			remainingPresets = append(remainingPresets, preset)
-		}
-	}
-
-	// Update config
-	config.Presets = remainingPresets
+	deletedCount := removePresets(presetNames, config)
 
 	// Save config
 	err := saveConfigWithError(config)
@@ -122,3 +124,44 @@ func deleteMultiplePresets(presetNames []string, config *setup.Config) {
 	// Return to management menu
 	ShowManagementMenu(config)
 }
+
+// removePresets drops the named presets from the config and returns how many were removed.
+// Presets extending a deleted preset keep their resolved settings.
+func removePresets(presetNames []string, config *setup.Config) int {
+	for _, name := range presetNames {
+		preset := setup.FindPreset(name, config.Presets)
+		if preset == nil {
+			continue
+		}
+		for _, child := range setup.PresetChildren(preset.ID, config.Presets) {
+			if containsPresetName(presetNames, child) {
+				continue
+			}
+			if err := setup.DetachPreset(child, config.Presets); err != nil {
+				fmt.Printf("%s %s %s\n", WarningText("Warning:"), NormalText(fmt.Sprintf("Could not detach '%s':", child)), NormalText(err.Error()))
+			}
+		}
+	}
+
+	var remainingPresets []setup.Preset
+	var deletedCount int
+	for _, preset := range config.Presets {
+		if containsPresetName(presetNames, preset.Name) {
+			deletedCount++
+		} else {
+			remainingPresets = append(remainingPresets, preset)
+		}
+	}
+	config.Presets = remainingPresets
+	return deletedCount
+}
+
+// containsPresetName checks whether a preset name is part of a list
+func containsPresetName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/huh"
+	"ledger-live-starter/cmd/ledger-live/parameters"
 	"ledger-live-starter/cmd/ledger-live/setup"
 )
 
@@ -115,58 +116,105 @@ func EditSinglePreset(presetName string, config *setup.Config) {
 
 	// Create a single form with all fields prefilled
 	var newName string = currentPreset.Name
+	var newExtends string = currentPreset.Extends
 	var newPlatform string = currentPreset.Platform
-	var selectedParameterNames []string = currentPreset.Parameters
-
-	// Create platform options with current selection
-	var platformOptions []huh.Option[string]
-	platformOptions = append(platformOptions, huh.NewOption("Mobile", "mobile"))
-	platformOptions = append(platformOptions, huh.NewOption("Desktop", "desktop"))
-
-	// Create parameter options with current selections
-	var parameterOptions []huh.Option[string]
-	for _, param := range config.Parameters {
-		option := huh.NewOption(param.Name, param.Name)
-		// Check if this parameter should be preselected
-		for _, selectedName := range currentPreset.Parameters {
-			if param.Name == selectedName {
-				option = option.Selected(true)
-				break
-			}
+	var selectedParameterIDs []string = append([]string{}, currentPreset.Parameters...)
+	var removedParameterIDs []string = append([]string{}, currentPreset.RemoveParameters...)
+	var newNote string = currentPreset.Note
+	var newWorkDir string = currentPreset.WorkDir
+	var newArgs string = setup.FormatArgs(currentPreset.Args)
+	var newEnv string = setup.FormatEnvOverrides(currentPreset.Env)
+
+	// candidate builds the preset as currently entered in the form
+	candidate := func(parameterIDs []string) setup.Preset {
+		return setup.Preset{
+			ID:               currentPreset.ID,
+			Name:             currentPreset.Name,
+			Extends:          newExtends,
+			Platform:         newPlatform,
+			Parameters:       parameterIDs,
+			RemoveParameters: removedParameterIDs,
 		}
-		parameterOptions = append(parameterOptions, option)
 	}
 
+	// Searchable, category grouped list of the preset's own parameters
+	parameterSearch, parameterPicker := parameters.NewParameterPicker(config.Parameters, &newPlatform, &selectedParameterIDs, "Local parameters (use SPACE to toggle):")
+
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
 				Title("Preset name:").
 				Value(&newName).
 				Validate(func(s string) error {
-					if strings.TrimSpace(s) == "" {
-						return fmt.Errorf("preset name cannot be empty")
-					}
-					// Allow keeping the same name
-					if s == currentPreset.Name {
-						return nil
-					}
-					for _, p := range config.Presets {
-						if p.Name == s {
-							return fmt.Errorf("preset '%s' already exists", s)
-						}
-					}
-					return nil
+					return ValidatePresetName(s, config.Presets, currentPreset.Name)
+				}),
+
+			huh.NewSelect[string]().
+				Title("Extends:").
+				Description("Inherit platform and parameters from another preset.").
+				Options(createBasePresetOptions(config.Presets, currentPreset.ID)...).
+				Value(&newExtends).
+				Validate(func(s string) error {
+					return setup.ValidateExtends(candidate(selectedParameterIDs), config.Presets)
 				}),
 			
 			huh.NewSelect[string]().
 				Title("Platform:").
-				Options(platformOptions...).
+				OptionsFunc(func() []huh.Option[string] {
+					return createPlatformOptions(newExtends != "")
+				}, &newExtends).
 				Value(&newPlatform),
-				
+		),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Inherited").
+				DescriptionFunc(func() string {
+					return describeInheritance(candidate(selectedParameterIDs), config)
+				}, &newExtends),
+
 			huh.NewMultiSelect[string]().
-				Title("Parameters (use SPACE to toggle):").
-				Options(parameterOptions...).
-				Value(&selectedParameterNames),
+				Title("Remove inherited parameters:").
+				OptionsFunc(func() []huh.Option[string] {
+					return createInheritedParameterOptions(candidate(selectedParameterIDs), config, removedParameterIDs)
+				}, &newExtends).
+				Value(&removedParameterIDs),
+				
+			parameterSearch,
+			parameterPicker.Validate(func(ids []string) error {
+				effective, err := setup.ResolvePreset(candidate(ids), config.Presets)
+				if err != nil {
+					return err
+				}
+				_, _, err = setup.ResolveParameterSelection(effective.Parameters, config.Parameters, effective.Platform)
+				return err
+			}),
+		),
+		huh.NewGroup(
+			huh.NewText().
+				Title("Note:").
+				Description("Shown in the start menu when the preset is highlighted.").
+				Value(&newNote),
+
+			huh.NewInput().
+				Title("Working directory:").
+				Description("Subdirectory of the Ledger Live path to run in, empty for the root.").
+				Placeholder("e.g. apps/ledger-live-mobile").
+				Value(&newWorkDir).
+				Validate(setup.ValidateWorkDir),
+
+			huh.NewText().
+				Title("Extra arguments:").
+				Description("One argument per line, appended to the command.").
+				Value(&newArgs),
+
+			huh.NewText().
+				Title("Env overrides:").
+				Description("One KEY=value per line, applied after the parameters.").
+				Value(&newEnv).
+				Validate(func(s string) error {
+					_, err := setup.ParseEnvOverrides(s)
+					return err
+				}),
 		),
 	)
 
@@ -177,10 +225,33 @@ func EditSinglePreset(presetName string, config *setup.Config) {
 		return
 	}
 
-	// Update the preset with new values
+	// Pull in required parameters (as local parameters) and enforce conflicts
+	effectivePreset, err := setup.ResolvePreset(candidate(selectedParameterIDs), config.Presets)
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		ShowEditPresetsMenu(config)
+		return
+	}
+	_, addedIDs, err := setup.ResolveParameterSelection(effectivePreset.Parameters, config.Parameters, effectivePreset.Platform)
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		ShowEditPresetsMenu(config)
+		return
+	}
+	if len(addedIDs) > 0 {
+		fmt.Printf("%s %s %s\n", InfoTextTitle("Info:"), NormalText("Added required parameters:"), HighlightText(strings.Join(setup.ParameterNames(addedIDs, config.Parameters), ", ")))
+	}
+
+	// Update the preset with new values, presets extending it refer to it by ID
 	config.Presets[presetIndex].Name = strings.TrimSpace(newName)
+	config.Presets[presetIndex].Extends = newExtends
 	config.Presets[presetIndex].Platform = newPlatform
-	config.Presets[presetIndex].Parameters = selectedParameterNames
+	config.Presets[presetIndex].Parameters = append(selectedParameterIDs, addedIDs...)
+	config.Presets[presetIndex].RemoveParameters = removedParameterIDs
+	config.Presets[presetIndex].Note = strings.TrimSpace(newNote)
+	config.Presets[presetIndex].WorkDir = strings.TrimSpace(newWorkDir)
+	config.Presets[presetIndex].Args = setup.ParseArgs(newArgs)
+	config.Presets[presetIndex].Env, _ = setup.ParseEnvOverrides(newEnv)
 
 	// Save changes
 	err = saveConfigWithError(config)
@@ -0,0 +1,60 @@
+package presets
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+// ShowFavoritesMenu lets the user pin presets to the top of the start menu
+func ShowFavoritesMenu(config *setup.Config) {
+	if len(config.Presets) == 0 {
+		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("No presets available."))
+		return
+	}
+
+	var options []huh.Option[string]
+	for _, preset := range config.Presets {
+		option := huh.NewOption(preset.Name, preset.Name)
+		if preset.Favorite {
+			option = option.Selected(true)
+		}
+		options = append(options, option)
+	}
+
+	var favorites []string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Favorite presets:").
+				Description("Favorites are pinned to the top of the start menu (use SPACE to toggle).").
+				Options(options...).
+				Value(&favorites),
+		),
+	)
+
+	err := RunStyledForm(form)
+	if err != nil {
+		ShowCancellationMessage()
+		ShowManagementMenu(config)
+		return
+	}
+
+	// Update favorite flags
+	for i := range config.Presets {
+		config.Presets[i].Favorite = containsPresetName(favorites, config.Presets[i].Name)
+	}
+
+	err = saveConfigWithError(config)
+	if err != nil {
+		ShowManagementMenu(config)
+		return
+	}
+
+	fmt.Printf("%s %s %s\n", SuccessText("Success:"), HighlightText(fmt.Sprintf("%d", len(favorites))), NormalText("favorite preset(s) saved."))
+
+	// Return to management menu
+	ShowManagementMenu(config)
+}
@@ -0,0 +1,123 @@
+package presets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+// Group menu values for leaving the group and creating a new one
+const (
+	noGroupOption  = "__none__"
+	newGroupOption = "__new__"
+)
+
+// ShowMoveToGroupMenu moves the selected presets into a start menu group
+func ShowMoveToGroupMenu(config *setup.Config) {
+	if len(config.Presets) == 0 {
+		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("No presets available."))
+		return
+	}
+
+	var presetOptions []huh.Option[string]
+	for _, preset := range config.Presets {
+		label := preset.Name
+		if group := strings.TrimSpace(preset.Group); group != "" {
+			label = fmt.Sprintf("%s %s", InfoTextTitle(group+" ›"), preset.Name)
+		}
+		presetOptions = append(presetOptions, huh.NewOption(label, preset.Name))
+	}
+
+	groupOptions := []huh.Option[string]{huh.NewOption("New group...", newGroupOption)}
+	for _, group := range setup.PresetGroups(config.Presets) {
+		groupOptions = append(groupOptions, huh.NewOption(group, group))
+	}
+	groupOptions = append(groupOptions, huh.NewOption("No group (top level)", noGroupOption))
+
+	var selectedPresets []string
+	var targetGroup string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Select presets to move:").
+				Description("Use SPACE to select presets").
+				Options(presetOptions...).
+				Value(&selectedPresets).
+				Validate(func(selected []string) error {
+					if len(selected) == 0 {
+						return fmt.Errorf("please select at least one preset")
+					}
+					return nil
+				}),
+			huh.NewSelect[string]().
+				Title("Move to group:").
+				Options(groupOptions...).
+				Value(&targetGroup),
+		),
+	)
+
+	err := RunStyledForm(form)
+	if err != nil {
+		ShowCancellationMessage()
+		ShowManagementMenu(config)
+		return
+	}
+
+	switch targetGroup {
+	case noGroupOption:
+		targetGroup = ""
+	case newGroupOption:
+		targetGroup, err = getNewGroupName()
+		if err != nil {
+			ShowCancellationMessage()
+			ShowManagementMenu(config)
+			return
+		}
+	}
+
+	setup.MovePresetsToGroup(selectedPresets, targetGroup, config.Presets)
+
+	err = saveConfigWithError(config)
+	if err != nil {
+		ShowManagementMenu(config)
+		return
+	}
+
+	destination := "the top level"
+	if targetGroup != "" {
+		destination = fmt.Sprintf("group '%s'", targetGroup)
+	}
+	fmt.Printf("%s %s %s\n", SuccessText("Success:"), HighlightText(fmt.Sprintf("%d", len(selectedPresets))), NormalText(fmt.Sprintf("preset(s) moved to %s.", destination)))
+
+	// Return to management menu
+	ShowManagementMenu(config)
+}
+
+// getNewGroupName asks for the name of a new group
+func getNewGroupName() (string, error) {
+	var name string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Group name:").
+				Placeholder("e.g. QA, Release testing, Performance").
+				Value(&name).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("group name cannot be empty")
+					}
+					return nil
+				}),
+		),
+	)
+
+	err := RunStyledForm(form)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(name), nil
+}
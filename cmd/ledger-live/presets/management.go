@@ -17,6 +17,12 @@ func ShowManagementMenu(config *setup.Config) {
 	
 	// Add delete presets option
 	options = append(options, huh.NewOption("Delete presets", "delete"))
+
+	// Add favorites option
+	options = append(options, huh.NewOption("Favorite presets", "favorites"))
+
+	// Add groups option
+	options = append(options, huh.NewOption("Move presets to group", "groups"))
 	
 	// Add back option last
 	options = append(options, huh.NewOption("Back", "back"))
@@ -52,5 +58,11 @@ func ShowManagementMenu(config *setup.Config) {
 	} else if selected == "delete" {
 		// Show delete presets menu
 		ShowDeletePresetsMenu(config)
+	} else if selected == "favorites" {
+		// Show favorites menu
+		ShowFavoritesMenu(config)
+	} else if selected == "groups" {
+		// Show move to group menu
+		ShowMoveToGroupMenu(config)
 	}
 }
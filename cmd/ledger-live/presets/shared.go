@@ -2,6 +2,8 @@ package presets
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/huh"
@@ -10,9 +12,11 @@ import (
 
 // CommandInfo represents command information - duplicate here to avoid circular imports
 type CommandInfo struct {
-	BaseCommand string
-	EnvVars     map[string]string
-	WorkingDir  string
+	BaseCommand   string
+	Args          []string
+	EnvVars       map[string]string
+	SecretEnvVars map[string]setup.Parameter
+	WorkingDir    string
 }
 
 // loadConfigWithDefaults loads config with consistent error handling
@@ -25,40 +29,150 @@ func loadConfigWithDefaults() (*setup.Config, error) {
 	return config, nil
 }
 
-// convertPlatformToKey converts platform command to platform key
-func convertPlatformToKey(platformCommand string) string {
-	switch platformCommand {
-	case "pnpm dev:llm":
-		return "mobile"
-	case "pnpm dev:lld":
-		return "desktop"
-	default:
-		return "mobile"
-	}
-}
-
-// extractParameterNames converts parameter structs to names
-func extractParameterNames(selectedParams []setup.Parameter) []string {
-	var parameterNames []string
+// extractParameterIDs converts parameter structs to the IDs presets refer to them by
+func extractParameterIDs(selectedParams []setup.Parameter) []string {
+	var parameterIDs []string
 	for _, param := range selectedParams {
-		parameterNames = append(parameterNames, param.Name)
+		parameterIDs = append(parameterIDs, param.ID)
 	}
-	return parameterNames
+	return parameterIDs
 }
 
-// displayPresetSummary shows a formatted preset summary
-func displayPresetSummary(preset setup.Preset) {
+// displayPresetSummary shows a formatted preset summary, naming the entries it refers to
+func displayPresetSummary(preset setup.Preset, config *setup.Config) {
 	fmt.Println(TitleText("Preset Summary:"))
 	fmt.Printf("   %s %s\n", InfoTextTitle("Name:"), HighlightText(preset.Name))
-	fmt.Printf("   %s %s\n", InfoTextTitle("Platform:"), HighlightText(strings.Title(preset.Platform)))
+	if preset.Extends != "" {
+		fmt.Printf("   %s %s\n", InfoTextTitle("Extends:"), HighlightText(setup.PresetName(preset.Extends, config.Presets)))
+	}
+	if group := strings.TrimSpace(preset.Group); group != "" {
+		fmt.Printf("   %s %s\n", InfoTextTitle("Group:"), HighlightText(group))
+	}
+	if preset.Note != "" {
+		fmt.Printf("   %s %s\n", InfoTextTitle("Note:"), NormalText(preset.Note))
+	}
+	if preset.WorkDir != "" {
+		fmt.Printf("   %s %s\n", InfoTextTitle("Working directory:"), HighlightText(preset.WorkDir))
+	}
+	if len(preset.Args) > 0 {
+		fmt.Printf("   %s %s\n", InfoTextTitle("Arguments:"), HighlightText(strings.Join(preset.Args, " ")))
+	}
+	if len(preset.Env) > 0 {
+		env, err := expandedEnvOverrides(preset, config)
+		if err != nil {
+			env = preset.Env
+		}
+		fmt.Printf("   %s %s\n", InfoTextTitle("Env overrides:"), NormalText(strings.ReplaceAll(setup.FormatEnvOverrides(env), "\n", ", ")))
+		if err != nil {
+			fmt.Printf("   %s %s\n", WarningText("Warning:"), NormalText(fmt.Sprintf("Env overrides cannot be expanded (%v)", err)))
+		}
+	}
+	if preset.Platform != "" {
+		fmt.Printf("   %s %s\n", InfoTextTitle("Platform:"), HighlightText(setup.PlatformLabel(preset.Platform)))
+	} else {
+		fmt.Printf("   %s %s\n", InfoTextTitle("Platform:"), NormalText("Inherited"))
+	}
+	if len(preset.RemoveParameters) > 0 {
+		fmt.Printf("   %s %s\n", InfoTextTitle("Removed parameters:"), NormalText(strings.Join(setup.ParameterNames(preset.RemoveParameters, config.Parameters), ", ")))
+	}
 	if len(preset.Parameters) > 0 {
-		fmt.Printf("   %s %s\n", InfoTextTitle("Parameters:"), NormalText(strings.Join(preset.Parameters, ", ")))
+		fmt.Printf("   %s %s\n", InfoTextTitle("Parameters:"), NormalText(strings.Join(setup.ParameterNames(preset.Parameters, config.Parameters), ", ")))
 	} else {
 		fmt.Printf("   %s %s\n", InfoTextTitle("Parameters:"), NormalText("None"))
 	}
 	fmt.Println()
 }
 
+// expandedEnvOverrides returns the preset's env overrides as the command receives them,
+// with ${VAR} references and $(command) substitutions expanded
+func expandedEnvOverrides(preset setup.Preset, config *setup.Config) (map[string]string, error) {
+	cmdInfo, err := BuildPresetCommand(&preset, config)
+	if err != nil {
+		return nil, err
+	}
+	expanded := make(map[string]string, len(preset.Env))
+	for key := range preset.Env {
+		expanded[key] = cmdInfo.EnvVars[key]
+	}
+	return expanded, nil
+}
+
+// createPlatformOptions creates platform options, optionally with an entry to inherit the base platform
+func createPlatformOptions(canInherit bool) []huh.Option[string] {
+	var options []huh.Option[string]
+	if canInherit {
+		options = append(options, huh.NewOption("Inherit from base", ""))
+	}
+	options = append(options, huh.NewOption("Mobile", "mobile"))
+	options = append(options, huh.NewOption("Desktop", "desktop"))
+	return options
+}
+
+// createBasePresetOptions creates options for choosing a base preset, valued by preset ID,
+// excluding the preset itself
+func createBasePresetOptions(presets []setup.Preset, excludeID string) []huh.Option[string] {
+	var options []huh.Option[string]
+	options = append(options, huh.NewOption("None", ""))
+	for _, preset := range presets {
+		if preset.ID != excludeID {
+			options = append(options, huh.NewOption(preset.Name, preset.ID))
+		}
+	}
+	return options
+}
+
+// createInheritedParameterOptions lists the parameters a preset inherits, preselecting removed ones
+func createInheritedParameterOptions(preset setup.Preset, config *setup.Config, removed []string) []huh.Option[string] {
+	var options []huh.Option[string]
+	base, err := setup.InheritedPreset(preset, config.Presets)
+	if err != nil || base == nil {
+		return options
+	}
+	for _, id := range base.Parameters {
+		option := huh.NewOption(setup.ParameterName(id, config.Parameters), id)
+		for _, r := range removed {
+			if r == id {
+				option = option.Selected(true)
+				break
+			}
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+// describeInheritance summarizes what a preset inherits from its base
+func describeInheritance(preset setup.Preset, config *setup.Config) string {
+	base, err := setup.InheritedPreset(preset, config.Presets)
+	if err != nil {
+		return err.Error()
+	}
+	if base == nil {
+		return "Nothing, this preset does not extend another one."
+	}
+
+	parameterList := "none"
+	if len(base.Parameters) > 0 {
+		parameterList = strings.Join(setup.ParameterNames(base.Parameters, config.Parameters), ", ")
+	}
+	return fmt.Sprintf("From '%s':\nPlatform: %s\nParameters: %s", setup.PresetName(preset.Extends, config.Presets), setup.PlatformLabel(base.Platform), parameterList)
+}
+
+// ValidatePresetName validates preset name for uniqueness and emptiness
+func ValidatePresetName(name string, existingPresets []setup.Preset, currentPresetName string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("preset name cannot be empty")
+	}
+
+	for _, p := range existingPresets {
+		// Allow keeping the same name if we're editing
+		if p.Name == name && p.Name != currentPresetName {
+			return fmt.Errorf("preset '%s' already exists", name)
+		}
+	}
+	return nil
+}
+
 // findPresetByName finds a preset by name and returns its index and pointer
 func findPresetByName(presetName string, presets []setup.Preset) (int, *setup.Preset) {
 	for i, preset := range presets {
@@ -69,6 +183,13 @@ func findPresetByName(presetName string, presets []setup.Preset) (int, *setup.Pr
 	return -1, nil
 }
 
+// recordPresetRun tracks a preset start for menu ordering, warning on failure
+func recordPresetRun(preset setup.Preset) {
+	if err := setup.RecordPresetRun(preset); err != nil {
+		fmt.Printf("%s %s\n", WarningText("Warning:"), NormalText(fmt.Sprintf("Could not record preset usage (%v)", err)))
+	}
+}
+
 // saveConfigWithError saves config and displays error if needed
 func saveConfigWithError(config *setup.Config) error {
 	err := setup.SaveConfig(config)
@@ -87,6 +208,9 @@ var (
 	HighlightText func(text string) string
 	NormalText    func(text string) string
 	WarningText   func(text string) string
+
+	// Info and warning messages go here, stderr while a command prints a machine-readable document
+	Diagnostics io.Writer = os.Stdout
 )
 
 // Form execution placeholders - these will be injected from main
@@ -100,8 +224,8 @@ var (
 var (
 	InputPresetName    func(existingPresets []setup.Preset) (string, error)
 	SelectPlatform     func() (string, string, error)
-	SelectParameters   func(availableParams []setup.Parameter) ([]setup.Parameter, error)
-	BuildPresetCommand func(preset *setup.Preset, config *setup.Config) *CommandInfo
+	SelectParameters   func(availableParams []setup.Parameter, platform string) ([]setup.Parameter, error)
+	BuildPresetCommand func(preset *setup.Preset, config *setup.Config) (*CommandInfo, error)
 	ExecuteCommand     func(cmdInfo *CommandInfo)
 	ShowMoreMenu       func(config *setup.Config)
 )
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage configuration profiles",
+	Long: `Profiles keep separate configurations, for example for development, release QA
+and demos. Each profile has its own presets, parameters, secrets and backups.
+
+The "default" profile is the configuration in ~/.ledger-live, other profiles live
+in ~/.ledger-live/profiles/<name>. Select one for every run with 'profile use', or
+for a single run with --profile or LEDGER_LIVE_STARTER_PROFILE.`,
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a profile with the default parameters",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setup.CreateProfile(args[0]); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText("Created profile"), HighlightText(args[0]))
+		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText(fmt.Sprintf("Switch to it with 'ledger-live profile use %s'.", args[0])))
+	},
+}
+
+var profileCopyCmd = &cobra.Command{
+	Use:               "copy <source> <name>",
+	Short:             "Create a profile from a copy of another one",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: firstArg(completeProfileNames),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setup.CopyProfile(args[0], args[1]); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s %s %s %s %s\n", SuccessText("Success:"), NormalText("Copied profile"), HighlightText(args[0]), NormalText("to"), HighlightText(args[1]))
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:               "use <name>",
+	Short:             "Select the profile used by default",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeProfileNames),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setup.UseProfile(args[0]); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText("Now using profile"), HighlightText(args[0]))
+		if setup.ConfigPathIsExplicit() {
+			fmt.Printf("%s %s\n", WarningText("Warning:"), NormalText("--config or LEDGER_LIVE_STARTER_CONFIG still takes precedence over profiles."))
+		}
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles, marking the active one",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		active := setup.ActiveProfile()
+		for _, name := range setup.ListProfiles() {
+			if name == active {
+				fmt.Printf("%s %s\n", SuccessText("*"), HighlightText(name))
+			} else {
+				fmt.Printf("  %s\n", NormalText(name))
+			}
+		}
+	},
+}
+
+var deleteProfileYes bool
+
+var profileDeleteCmd = &cobra.Command{
+	Use:               "delete <name>",
+	Short:             "Delete a profile and all its files",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeProfileNames),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if !setup.ProfileExists(name) {
+			exitWithError(fmt.Errorf("profile '%s' does not exist", name))
+		}
+
+		if !deleteProfileYes {
+			confirm := false
+			form := huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title(fmt.Sprintf("Delete profile '%s'?", name)).
+						Description("Its presets, parameters, secrets and backups are removed.").
+						Affirmative("Delete").
+						Negative("Cancel").
+						Value(&confirm),
+				),
+			)
+			if err := RunStyledForm(form); err != nil || !confirm {
+				ShowConfirmationCancelledMessage()
+				return
+			}
+		}
+
+		if err := setup.DeleteProfile(name); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText("Deleted profile"), HighlightText(name))
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileCopyCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileListCmd)
+
+	profileDeleteCmd.Flags().BoolVarP(&deleteProfileYes, "yes", "y", false, "delete without asking for confirmation")
+	profileCmd.AddCommand(profileDeleteCmd)
+	rootCmd.AddCommand(profileCmd)
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+// Name of the release asset listing the SHA-256 of every binary
+const checksumsAssetName = "checksums.txt"
+
+// Longest time a single download may take
+const selfUpdateDownloadTimeout = 5 * time.Minute
+
+var (
+	selfUpdateVersion  string
+	selfUpdateEndpoint string
+	selfUpdateChannel  string
+	selfUpdateForce    bool
+)
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update ledger-live to the latest release",
+	Long: `Download the release binary for this platform, verify it against the release's
+published checksums and replace the running binary. The previous binary is restored
+if anything goes wrong.
+
+Without --version the newest release on the update channel is installed: stable
+releases by default, pre-releases too on the beta channel (update_channel in the
+config, LEDGER_LIVE_STARTER_UPDATE_CHANNEL or --channel).
+
+The releases endpoint defaults to the one used by the update check (update_endpoint
+in the config or LEDGER_LIVE_STARTER_UPDATE_ENDPOINT); --endpoint overrides it, e.g.
+to test against a local server.`,
+	Example: `  ledger-live self-update
+  ledger-live self-update --version 1.4.0
+  ledger-live self-update --channel beta
+  ledger-live self-update --endpoint http://localhost:8000`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runSelfUpdate(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func runSelfUpdate() error {
+	settings := updateCheckSettings()
+	if selfUpdateEndpoint != "" {
+		settings.Endpoint = strings.TrimSuffix(selfUpdateEndpoint, "/")
+	}
+	if selfUpdateChannel != "" {
+		settings.Channel = selfUpdateChannel
+	}
+	if err := validateUpdateChannel(settings.Channel); err != nil {
+		return err
+	}
+
+	release, err := fetchSelfUpdateRelease(settings, selfUpdateVersion)
+	if err != nil {
+		return err
+	}
+
+	current := strings.TrimPrefix(Version, "v")
+	target := strings.TrimPrefix(release.TagName, "v")
+	if selfUpdateVersion == "" && !selfUpdateForce && Version != "dev" && !isNewerVersion(target, current) {
+		fmt.Printf("%s %s %s\n", SuccessText("Up to date:"), NormalText("ledger-live is already at"), HighlightText(current))
+		return nil
+	}
+
+	assetName := selfUpdateAssetName()
+	asset := findReleaseAsset(release, assetName)
+	if asset == nil {
+		return fmt.Errorf("release %s has no binary for %s/%s (%s)", release.TagName, runtime.GOOS, runtime.GOARCH, assetName)
+	}
+	checksums := findReleaseAsset(release, checksumsAssetName)
+	if checksums == nil {
+		return fmt.Errorf("release %s publishes no %s, refusing to install an unverified binary", release.TagName, checksumsAssetName)
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the running binary: %v", err)
+	}
+	if exePath, err = filepath.EvalSymlinks(exePath); err != nil {
+		return fmt.Errorf("failed to locate the running binary: %v", err)
+	}
+
+	fmt.Printf("%s %s %s\n", InfoTextTitle("Downloading:"), NormalText(assetName), HighlightText(release.TagName))
+	expected, err := fetchChecksum(checksums.BrowserDownloadURL, assetName)
+	if err != nil {
+		return err
+	}
+
+	// Download next to the binary so the final rename stays on one filesystem
+	newPath, err := downloadVerifiedBinary(asset.BrowserDownloadURL, filepath.Dir(exePath), expected)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(newPath)
+
+	if err := exec.Command(newPath, "version", "--output", "plain").Run(); err != nil {
+		return fmt.Errorf("downloaded binary does not run: %v", err)
+	}
+
+	if err := replaceBinary(exePath, newPath); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s %s\n", SuccessText("Success:"), NormalText("Updated ledger-live to"), HighlightText(target))
+	return nil
+}
+
+// fetchSelfUpdateRelease returns the pinned release, or the newest one on the channel when
+// version is empty
+func fetchSelfUpdateRelease(settings updateSettings, version string) (*GitHubRelease, error) {
+	if version == "" {
+		release, err := fetchChannelRelease(settings.Endpoint, settings.Channel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the latest release: %v", err)
+		}
+		return release, nil
+	}
+
+	if _, ok := parseSemver(version); !ok {
+		return nil, fmt.Errorf("'%s' is not a valid version", version)
+	}
+	tag := "v" + strings.TrimPrefix(version, "v")
+	release, err := fetchRelease(settings.Endpoint + "/releases/tags/" + tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release %s: %v", tag, err)
+	}
+	return release, nil
+}
+
+// selfUpdateAssetName returns the release binary built for this platform
+func selfUpdateAssetName() string {
+	name := fmt.Sprintf("ledger-live-%s-%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func findReleaseAsset(release *GitHubRelease, name string) *ReleaseAsset {
+	for i := range release.Assets {
+		if release.Assets[i].Name == name {
+			return &release.Assets[i]
+		}
+	}
+	return nil
+}
+
+// download GETs a URL, failing on any status other than 200
+func download(url string) (*http.Response, error) {
+	client := &http.Client{Timeout: selfUpdateDownloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %v", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	return resp, nil
+}
+
+// fetchChecksum reads the SHA-256 of an asset from a sha256sum-style checksums file
+func fetchChecksum(url, assetName string) (string, error) {
+	resp, err := download(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == assetName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", checksumsAssetName, err)
+	}
+	return "", fmt.Errorf("%s has no entry for %s", checksumsAssetName, assetName)
+}
+
+// downloadVerifiedBinary downloads a binary into dir and checks its SHA-256, returning the
+// path of the executable temp file
+func downloadVerifiedBinary(url, dir, expected string) (string, error) {
+	resp, err := download(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	file, err := os.CreateTemp(dir, ".ledger-live-update-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create the download file: %v", err)
+	}
+	path := file.Name()
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("failed to download %s: %v", url, err)
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		os.Remove(path)
+		return "", fmt.Errorf("checksum mismatch: expected %s, got %s", expected, actual)
+	}
+	if err := os.Chmod(path, 0755); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// replaceBinary swaps newPath in for exePath, putting the old binary back if the swap fails
+func replaceBinary(exePath, newPath string) error {
+	oldPath := exePath + ".old"
+	os.Remove(oldPath)
+
+	// Windows cannot overwrite a running binary but can rename it
+	if err := os.Rename(exePath, oldPath); err != nil {
+		return fmt.Errorf("failed to move the current binary aside: %v", err)
+	}
+	if err := os.Rename(newPath, exePath); err != nil {
+		if rollbackErr := os.Rename(oldPath, exePath); rollbackErr != nil {
+			return fmt.Errorf("failed to install the new binary (%v) and to restore the old one from %s (%v)", err, oldPath, rollbackErr)
+		}
+		return fmt.Errorf("failed to install the new binary, the previous one was restored: %v", err)
+	}
+
+	// The running binary is still in use on Windows; it is cleaned up by the next update
+	os.Remove(oldPath)
+	return nil
+}
+
+func init() {
+	selfUpdateCmd.Flags().StringVar(&selfUpdateVersion, "version", "", "install this version instead of the latest")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateEndpoint, "endpoint", "", "releases API base URL")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateChannel, "channel", "", "release channel: stable or beta")
+	selfUpdateCmd.RegisterFlagCompletionFunc("channel", completeValues(setup.ChannelStable, setup.ChannelBeta))
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "reinstall even when already up to date")
+	rootCmd.AddCommand(selfUpdateCmd)
+}
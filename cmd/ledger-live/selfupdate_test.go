@@ -0,0 +1,189 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// selfUpdateServer serves pinned releases by tag, the latest release and downloadable files
+func selfUpdateServer(t *testing.T, releases []GitHubRelease, files map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/releases":
+			json.NewEncoder(w).Encode(releases)
+		case strings.HasPrefix(r.URL.Path, "/releases/tags/"):
+			tag := strings.TrimPrefix(r.URL.Path, "/releases/tags/")
+			for _, release := range releases {
+				if release.TagName == tag {
+					json.NewEncoder(w).Encode(release)
+					return
+				}
+			}
+			http.NotFound(w, r)
+		case strings.HasPrefix(r.URL.Path, "/download/"):
+			content, ok := files[strings.TrimPrefix(r.URL.Path, "/download/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(content))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestFetchChecksum(t *testing.T) {
+	binary := selfUpdateAssetName()
+	checksums := strings.Join([]string{
+		"0000000000000000000000000000000000000000000000000000000000000000  ledger-live-other-os",
+		"ABCDEF0000000000000000000000000000000000000000000000000000000001 *" + binary,
+		"malformed line",
+		"",
+	}, "\n")
+	server := selfUpdateServer(t, nil, map[string]string{checksumsAssetName: checksums})
+	url := server.URL + "/download/" + checksumsAssetName
+
+	got, err := fetchChecksum(url, binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Binary mode markers are stripped and the hash is lower-cased
+	if want := "abcdef0000000000000000000000000000000000000000000000000000000001"; got != want {
+		t.Errorf("fetchChecksum() = %s, want %s", got, want)
+	}
+
+	if _, err := fetchChecksum(url, "ledger-live-unknown"); err == nil || !strings.Contains(err.Error(), "no entry") {
+		t.Errorf("expected a missing entry error, got %v", err)
+	}
+	if _, err := fetchChecksum(server.URL+"/download/missing.txt", binary); err == nil {
+		t.Error("expected an error when the checksums file cannot be downloaded")
+	}
+}
+
+func TestDownloadVerifiedBinary(t *testing.T) {
+	content := "#!/bin/sh\necho new\n"
+	server := selfUpdateServer(t, nil, map[string]string{"binary": content})
+	dir := t.TempDir()
+
+	path, err := downloadVerifiedBinary(server.URL+"/download/binary", dir, sha256Hex(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("downloaded %q, want %q", data, content)
+	}
+	os.Remove(path)
+
+	_, err = downloadVerifiedBinary(server.URL+"/download/binary", dir, sha256Hex("something else"))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected a checksum mismatch, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("a rejected download was left behind: %v", entries)
+	}
+}
+
+func TestFetchSelfUpdateReleasePinsVersion(t *testing.T) {
+	server := selfUpdateServer(t, []GitHubRelease{{TagName: "v1.5.0"}, {TagName: "v1.4.0"}}, nil)
+	settings := updateSettings{Endpoint: server.URL, Channel: "stable"}
+
+	for _, version := range []string{"1.4.0", "v1.4.0"} {
+		release, err := fetchSelfUpdateRelease(settings, version)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		if release.TagName != "v1.4.0" {
+			t.Errorf("%s: got %s, want v1.4.0", version, release.TagName)
+		}
+	}
+
+	latest, err := fetchSelfUpdateRelease(settings, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.TagName != "v1.5.0" {
+		t.Errorf("latest: got %s, want v1.5.0", latest.TagName)
+	}
+
+	if _, err := fetchSelfUpdateRelease(settings, "1.3.0"); err == nil {
+		t.Error("expected an error for a version that was never released")
+	}
+	if _, err := fetchSelfUpdateRelease(settings, "latest"); err == nil || !strings.Contains(err.Error(), "not a valid version") {
+		t.Errorf("expected an invalid version error, got %v", err)
+	}
+}
+
+func TestRunSelfUpdateRequiresAssets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	binary := selfUpdateAssetName()
+	server := selfUpdateServer(t, []GitHubRelease{
+		{TagName: "v9.0.0", Assets: []ReleaseAsset{{Name: checksumsAssetName}}},
+		{TagName: "v9.1.0", Assets: []ReleaseAsset{{Name: binary}}},
+	}, nil)
+
+	defer func(endpoint, version string) { selfUpdateEndpoint, selfUpdateVersion = endpoint, version }(selfUpdateEndpoint, selfUpdateVersion)
+	selfUpdateEndpoint = server.URL
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"9.0.0", "has no binary for"},
+		{"9.1.0", "refusing to install an unverified binary"},
+	}
+	for _, tt := range tests {
+		selfUpdateVersion = tt.version
+		if err := runSelfUpdate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.version, err, tt.want)
+		}
+	}
+}
+
+func TestReplaceBinary(t *testing.T) {
+	dir := t.TempDir()
+	exePath := filepath.Join(dir, "ledger-live")
+	newPath := filepath.Join(dir, "ledger-live-new")
+	os.WriteFile(exePath, []byte("old"), 0755)
+	os.WriteFile(newPath, []byte("new"), 0755)
+
+	if err := replaceBinary(exePath, newPath); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(exePath); string(data) != "new" {
+		t.Errorf("binary = %q, want the new one", data)
+	}
+	if _, err := os.Stat(exePath + ".old"); !os.IsNotExist(err) {
+		t.Error("the previous binary was not cleaned up")
+	}
+}
+
+func TestReplaceBinaryRollsBack(t *testing.T) {
+	dir := t.TempDir()
+	exePath := filepath.Join(dir, "ledger-live")
+	os.WriteFile(exePath, []byte("old"), 0755)
+
+	// The new binary is missing, so moving it into place fails
+	err := replaceBinary(exePath, filepath.Join(dir, "missing"))
+	if err == nil || !strings.Contains(err.Error(), "previous one was restored") {
+		t.Fatalf("expected a rollback error, got %v", err)
+	}
+	if data, _ := os.ReadFile(exePath); string(data) != "old" {
+		t.Errorf("binary = %q, want the previous one restored", data)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// semVersion is a parsed semantic version, see https://semver.org
+type semVersion struct {
+	Major, Minor, Patch int
+	PreRelease          []string // Dot separated pre-release identifiers, e.g. beta.2
+}
+
+// parseSemver parses versions such as v1.10.0 or 2.0.0-beta.1+build.5. Missing minor and
+// patch numbers count as zero and build metadata is ignored.
+func parseSemver(version string) (semVersion, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "+")
+	core, preRelease, hasPreRelease := strings.Cut(version, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return semVersion{}, false
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semVersion{}, false
+		}
+		numbers[i] = n
+	}
+
+	parsed := semVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}
+	if hasPreRelease {
+		if preRelease == "" {
+			return semVersion{}, false
+		}
+		parsed.PreRelease = strings.Split(preRelease, ".")
+	}
+	return parsed, true
+}
+
+// IsPreRelease reports whether the version has a pre-release tag
+func (v semVersion) IsPreRelease() bool {
+	return len(v.PreRelease) > 0
+}
+
+// compareSemver returns -1, 0 or 1 as a is older than, equal to or newer than b.
+// A pre-release is older than the release it precedes.
+func compareSemver(a, b semVersion) int {
+	for _, diff := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if diff != 0 {
+			return sign(diff)
+		}
+	}
+
+	switch {
+	case !a.IsPreRelease() && !b.IsPreRelease():
+		return 0
+	case !a.IsPreRelease():
+		return 1
+	case !b.IsPreRelease():
+		return -1
+	}
+
+	for i := 0; i < len(a.PreRelease) && i < len(b.PreRelease); i++ {
+		if c := comparePreReleaseIdentifier(a.PreRelease[i], b.PreRelease[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(a.PreRelease) - len(b.PreRelease))
+}
+
+// comparePreReleaseIdentifier compares numeric identifiers numerically and others in
+// ASCII order, numeric ones sorting first
+func comparePreReleaseIdentifier(a, b string) int {
+	aNumber, aErr := strconv.Atoi(a)
+	bNumber, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return sign(aNumber - bNumber)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestCompareSemver(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.9.0", 1},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-beta", "1.0.0", -1},
+		{"1.0.0-rc.1", "1.0.0-beta.2", 1},
+		{"1.0.0-beta.2", "1.0.0-beta.10", -1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		a, ok := parseSemver(tt.a)
+		if !ok {
+			t.Fatalf("parseSemver(%q) failed", tt.a)
+		}
+		b, ok := parseSemver(tt.b)
+		if !ok {
+			t.Fatalf("parseSemver(%q) failed", tt.b)
+		}
+		if got := compareSemver(a, b); got != tt.want {
+			t.Errorf("compareSemver(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := compareSemver(b, a); got != -tt.want {
+			t.Errorf("compareSemver(%s, %s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestParseSemverRejectsInvalid(t *testing.T) {
+	for _, version := range []string{"", "dev", "v", "1.2.3.4", "1.x.0", "1.-2.0", "1.0.0-", "1..0"} {
+		if _, ok := parseSemver(version); ok {
+			t.Errorf("parseSemver(%q) succeeded, want failure", version)
+		}
+	}
+}
+
+func TestIsNewerVersionIgnoresInvalid(t *testing.T) {
+	if isNewerVersion("unknown", "1.0.0") || isNewerVersion("2.0.0", "dev") {
+		t.Error("an invalid version must never count as newer")
+	}
+}
@@ -0,0 +1,57 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteFileAtomicAppliesPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no Unix permission bits")
+	}
+	path := filepath.Join(t.TempDir(), "vault.json")
+
+	// Replacing a more open file must not keep its permissions
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("permissions = %o, want 600", perm)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "secret" {
+		t.Errorf("contents = %q, want %q", data, "secret")
+	}
+	if matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".vault.json.tmp-*")); len(matches) > 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestWriteVaultIsPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no Unix permission bits")
+	}
+	useTempConfig(t, &Config{LedgerLivePath: t.TempDir()})
+	vaultPassphrase = "test passphrase"
+	t.Cleanup(func() { vaultPassphrase = "" })
+
+	if err := writeVault(map[string]string{"abc": "value"}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(GetVaultPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("vault permissions = %o, want 600", perm)
+	}
+}
@@ -0,0 +1,165 @@
+package setup
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxConfigBackups is the number of previous config versions kept
+const MaxConfigBackups = 10
+
+// Backup directory name, next to the config file
+const backupDirName = "backups"
+
+// Timestamp layout used in backup file names; sorts chronologically
+const backupTimeFormat = "20060102-150405.000"
+
+// ConfigBackup is a previous version of the config file
+type ConfigBackup struct {
+	Path       string
+	Time       time.Time
+	Parameters int
+	Presets    int
+}
+
+// getBackupDir returns the backup directory for a config file
+func getBackupDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), backupDirName)
+}
+
+// backupPrefix returns the file name prefix of a config file's backups
+func backupPrefix(configPath string) string {
+	base := filepath.Base(configPath)
+	return strings.TrimSuffix(base, filepath.Ext(base)) + "-"
+}
+
+// backupConfigFile copies the current config file into the backups before it is overwritten,
+// skipping unchanged content and dropping the oldest backups beyond MaxConfigBackups
+func backupConfigFile(configPath string, newData []byte) error {
+	current, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(current, newData) {
+		return nil
+	}
+
+	dir := getBackupDir(configPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	name := backupPrefix(configPath) + time.Now().Format(backupTimeFormat) + filepath.Ext(configPath)
+	if err := WriteFileAtomic(filepath.Join(dir, name), current, 0644); err != nil {
+		return err
+	}
+
+	return pruneConfigBackups(configPath)
+}
+
+// pruneConfigBackups removes the oldest backups beyond MaxConfigBackups
+func pruneConfigBackups(configPath string) error {
+	backups, err := ListConfigBackups(configPath)
+	if err != nil {
+		return err
+	}
+	for i := MaxConfigBackups; i < len(backups); i++ {
+		if err := os.Remove(backups[i].Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListConfigBackups returns the backups of a config file, newest first
+func ListConfigBackups(configPath string) ([]ConfigBackup, error) {
+	dir := getBackupDir(configPath)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := backupPrefix(configPath)
+	ext := filepath.Ext(configPath)
+	var backups []ConfigBackup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		created, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+
+		backup := ConfigBackup{Path: filepath.Join(dir, name), Time: created}
+		if data, err := os.ReadFile(backup.Path); err == nil {
+			if config, err := decodeConfig(data, FormatForPath(configPath)); err == nil {
+				backup.Parameters = len(config.Parameters)
+				backup.Presets = len(config.Presets)
+			}
+		}
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// UndoConfigChange restores the most recent backup and removes it, so repeated
+// calls step further back in history
+func UndoConfigChange(configPath string) (*ConfigBackup, error) {
+	var restored *ConfigBackup
+	err := WithFileLock(configPath, func() error {
+		backups, err := ListConfigBackups(configPath)
+		if err != nil {
+			return err
+		}
+		if len(backups) == 0 {
+			return fmt.Errorf("no backups found in %s", getBackupDir(configPath))
+		}
+
+		latest := backups[0]
+		data, err := os.ReadFile(latest.Path)
+		if err != nil {
+			return err
+		}
+		if err := WriteFileAtomic(configPath, data, 0644); err != nil {
+			return err
+		}
+		if err := os.Remove(latest.Path); err != nil {
+			return err
+		}
+		restored = &latest
+		return nil
+	})
+	return restored, err
+}
+
+// RestoreConfigBackup replaces the config with a backup, backing up the current
+// config first so the restore can itself be undone
+func RestoreConfigBackup(configPath string, backup ConfigBackup) error {
+	return WithFileLock(configPath, func() error {
+		data, err := os.ReadFile(backup.Path)
+		if err != nil {
+			return err
+		}
+		if err := backupConfigFile(configPath, data); err != nil {
+			return fmt.Errorf("failed to back up current config: %v", err)
+		}
+		return WriteFileAtomic(configPath, data, 0644)
+	})
+}
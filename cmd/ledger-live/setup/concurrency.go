@@ -0,0 +1,216 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/charmbracelet/huh"
+)
+
+// ErrConfigReloaded is returned by SaveConfig when the config changed on disk and the
+// user chose to reload it; the config was replaced with the version on disk
+var ErrConfigReloaded = errors.New("the configuration changed on disk and was reloaded, your changes were discarded")
+
+// How to handle a config that changed on disk since it was loaded
+const (
+	conflictMerge     = "merge"
+	conflictReload    = "reload"
+	conflictOverwrite = "overwrite"
+)
+
+// resolveDiskChange handles a config that was changed by another instance since it was
+// loaded. It updates config in place, based on current, and reports whether it should
+// still be written. Without a terminal to prompt on, the changes are merged.
+func resolveDiskChange(config *Config, path string, current []byte) (bool, error) {
+	disk, err := parseConfigData(current, path)
+	if err != nil {
+		return false, fmt.Errorf("the configuration changed on disk and cannot be read: %v", err)
+	}
+
+	action := conflictMerge
+	if RunStyledForm != nil && (CanPrompt == nil || CanPrompt()) {
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title("The configuration was changed by another ledger-live instance").
+					Description("Choose how to save your changes.").
+					Options(
+						huh.NewOption("Merge my changes into the new version", conflictMerge),
+						huh.NewOption("Reload and discard my changes", conflictReload),
+						huh.NewOption("Overwrite with my version", conflictOverwrite),
+					).
+					Value(&action),
+			),
+		)
+		if err := RunStyledForm(form); err != nil {
+			return false, fmt.Errorf("save cancelled, the configuration changed on disk")
+		}
+	}
+
+	switch action {
+	case conflictReload:
+		*config = *disk
+		config.loadedPath = path
+		config.loadedData = current
+		return false, ErrConfigReloaded
+	case conflictOverwrite:
+		config.loadedData = current
+		return true, nil
+	}
+
+	base, err := parseConfigData(config.loadedData, path)
+	if err != nil {
+		return false, err
+	}
+	// Entries added since loading need their IDs to be told apart from the other version's
+	EnsureIDs(config)
+	merged, conflicts := MergeConfigs(base, config, disk)
+	*config = *merged
+	config.loadedPath = path
+	config.loadedData = current
+	for _, conflict := range conflicts {
+		fmt.Fprintf(Diagnostics, "%s %s\n", WarningText("Warning:"), NormalText(conflict))
+	}
+	return true, nil
+}
+
+// parseConfigData parses config file contents, migrating older schema versions in memory
+func parseConfigData(data []byte, path string) (*Config, error) {
+	migrated, _, _, err := migrateConfigData(data, path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMigratedConfig(migrated)
+}
+
+// MergeConfigs applies the changes made in mine (relative to base) on top of theirs.
+// Parameters and presets are matched by ID. When both sides changed the same entry,
+// mine wins and the returned messages describe what was overridden.
+func MergeConfigs(base *Config, mine *Config, theirs *Config) (*Config, []string) {
+	merged := *theirs
+	var conflicts []string
+
+	if mine.LedgerLivePath != base.LedgerLivePath {
+		if theirs.LedgerLivePath != base.LedgerLivePath && theirs.LedgerLivePath != mine.LedgerLivePath {
+			conflicts = append(conflicts, "Ledger Live path was changed in both versions, keeping yours")
+		}
+		merged.LedgerLivePath = mine.LedgerLivePath
+	}
+	if !reflect.DeepEqual(mine.UpdateCheck, base.UpdateCheck) {
+		merged.UpdateCheck = mine.UpdateCheck
+	}
+	if mine.UpdateEndpoint != base.UpdateEndpoint {
+		merged.UpdateEndpoint = mine.UpdateEndpoint
+	}
+	if mine.UpdateChannel != base.UpdateChannel {
+		merged.UpdateChannel = mine.UpdateChannel
+	}
+	if !reflect.DeepEqual(mine.Theme, base.Theme) {
+		merged.Theme = mine.Theme
+	}
+
+	var paramConflicts, presetConflicts []string
+	var paramRenames, presetRenames []entryRename
+	merged.Parameters, paramConflicts, paramRenames = mergeEntries(base.Parameters, mine.Parameters, theirs.Parameters,
+		func(p Parameter) string { return p.ID }, func(p Parameter) string { return p.Name },
+		func(p Parameter, name string) Parameter { p.Name = name; return p })
+	merged.Presets, presetConflicts, presetRenames = mergeEntries(base.Presets, mine.Presets, theirs.Presets,
+		func(p Preset) string { return p.ID }, func(p Preset) string { return p.Name },
+		func(p Preset, name string) Preset { p.Name = name; return p })
+
+	for _, name := range paramConflicts {
+		conflicts = append(conflicts, fmt.Sprintf("Parameter '%s' was changed in both versions, keeping yours", name))
+	}
+	for _, rename := range paramRenames {
+		conflicts = append(conflicts, fmt.Sprintf("Parameter '%s' was also added in the other version, yours was saved as '%s'", rename.From, rename.To))
+	}
+	for _, name := range presetConflicts {
+		conflicts = append(conflicts, fmt.Sprintf("Preset '%s' was changed in both versions, keeping yours", name))
+	}
+	for _, rename := range presetRenames {
+		conflicts = append(conflicts, fmt.Sprintf("Preset '%s' was also added in the other version, yours was saved as '%s'", rename.From, rename.To))
+	}
+	return &merged, conflicts
+}
+
+// entryRename records an added entry renamed because its name was taken in the other version
+type entryRename struct {
+	From string
+	To   string
+}
+
+// mergeEntries three-way merges lists of entries keyed by ID, keeping the order of theirs
+// and appending entries added in mine. It returns the names of entries changed on both sides,
+// and the entries added in mine that were renamed because the other side added the same name.
+func mergeEntries[T any](base, mine, theirs []T, id func(T) string, name func(T) string, rename func(T, string) T) ([]T, []string, []entryRename) {
+	baseByID := indexEntries(base, id)
+	mineByID := indexEntries(mine, id)
+	theirsByID := indexEntries(theirs, id)
+
+	var merged []T
+	var conflicts []string
+	var added []T
+
+	for _, their := range theirs {
+		key := id(their)
+		original, inBase := baseByID[key]
+		my, inMine := mineByID[key]
+
+		switch {
+		case inMine && (!inBase || !reflect.DeepEqual(original, my)):
+			// Changed or added on my side
+			if (!inBase || !reflect.DeepEqual(original, their)) && !reflect.DeepEqual(my, their) {
+				conflicts = append(conflicts, name(my))
+			}
+			merged = append(merged, my)
+		case !inMine && inBase && reflect.DeepEqual(original, their):
+			// Deleted on my side and untouched on theirs
+		default:
+			merged = append(merged, their)
+		}
+	}
+
+	for _, my := range mine {
+		key := id(my)
+		if _, inTheirs := theirsByID[key]; inTheirs {
+			continue
+		}
+		original, inBase := baseByID[key]
+		// Keep my additions, and my edits of entries they deleted
+		if !inBase {
+			added = append(added, my)
+		} else if !reflect.DeepEqual(original, my) {
+			merged = append(merged, my)
+		}
+	}
+
+	// Names must stay unique, so my additions give way to entries of the same name
+	taken := make(map[string]bool)
+	for _, entry := range merged {
+		taken[name(entry)] = true
+	}
+	var renames []entryRename
+	for _, my := range added {
+		if original := name(my); taken[original] {
+			unique := original
+			for n := 2; taken[unique]; n++ {
+				unique = fmt.Sprintf("%s (%d)", original, n)
+			}
+			my = rename(my, unique)
+			renames = append(renames, entryRename{From: original, To: unique})
+		}
+		taken[name(my)] = true
+		merged = append(merged, my)
+	}
+
+	return merged, conflicts, renames
+}
+
+func indexEntries[T any](entries []T, id func(T) string) map[string]T {
+	index := make(map[string]T, len(entries))
+	for _, entry := range entries {
+		index[id(entry)] = entry
+	}
+	return index
+}
@@ -0,0 +1,379 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/huh"
+)
+
+// useTempConfig points the config path at a fresh file in a temporary directory
+func useTempConfig(t *testing.T, config *Config) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv(envNoRepoConfig, "1")
+	for _, setting := range envSettings {
+		t.Setenv(SettingEnvVar(setting.key), "")
+	}
+
+	path := filepath.Join(dir, "config.json")
+	SetConfigPath(path)
+	t.Cleanup(func() { SetConfigPath("") })
+
+	if err := SaveConfigToPath(config, path); err != nil {
+		t.Fatalf("writing initial config: %v", err)
+	}
+	return path
+}
+
+func loadTestConfig(t *testing.T) *Config {
+	t.Helper()
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+	return config
+}
+
+func TestConcurrentWritersKeepDisjointEdits(t *testing.T) {
+	path := useTempConfig(t, &Config{
+		LedgerLivePath: t.TempDir(),
+		Parameters:     []Parameter{{Name: "Debug", EnvVar: "DEBUG=1"}},
+		Presets:        []Preset{{Name: "Dev", Platform: "desktop", Parameters: []string{"Debug"}}},
+	})
+
+	first := loadTestConfig(t)
+	second := loadTestConfig(t)
+
+	first.Parameters = append(first.Parameters, Parameter{Name: "Verbose", EnvVar: "VERBOSE=1"})
+	if err := SaveConfigToPath(first, path); err != nil {
+		t.Fatalf("first save: %v", err)
+	}
+
+	// The second writer loaded the config before the first one saved
+	second.Presets[0].Note = "edited by the second writer"
+	if err := SaveConfigToPath(second, path); err != nil {
+		t.Fatalf("second save: %v", err)
+	}
+
+	saved := loadTestConfig(t)
+	if FindParameter("Verbose", saved.Parameters) == nil {
+		t.Errorf("the first writer's parameter was lost: %+v", saved.Parameters)
+	}
+	if preset := FindPreset("Dev", saved.Presets); preset == nil || preset.Note != "edited by the second writer" {
+		t.Errorf("the second writer's preset edit was lost: %+v", saved.Presets)
+	}
+}
+
+func TestConcurrentWritersWithoutPromptMerge(t *testing.T) {
+	path := useTempConfig(t, &Config{LedgerLivePath: t.TempDir()})
+
+	// Without a terminal the conflict prompt must not be shown
+	prompted := false
+	RunStyledForm = func(*huh.Form) error {
+		prompted = true
+		return nil
+	}
+	CanPrompt = func() bool { return false }
+	defer func() {
+		RunStyledForm = nil
+		CanPrompt = nil
+	}()
+
+	first := loadTestConfig(t)
+	second := loadTestConfig(t)
+	first.Parameters = append(first.Parameters, Parameter{Name: "A", EnvVar: "A=1"})
+	second.Parameters = append(second.Parameters, Parameter{Name: "B", EnvVar: "B=1"})
+	if err := SaveConfigToPath(first, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveConfigToPath(second, path); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := loadTestConfig(t)
+	if prompted || len(saved.Parameters) != 2 {
+		t.Errorf("expected both parameters merged without prompting, got %+v", saved.Parameters)
+	}
+}
+
+// disablePrompt makes disk changes merge without asking, as without a terminal
+func disablePrompt(t *testing.T) {
+	t.Helper()
+	CanPrompt = func() bool { return false }
+	t.Cleanup(func() { CanPrompt = nil })
+}
+
+func TestParallelSavesKeepEveryAddition(t *testing.T) {
+	path := useTempConfig(t, &Config{LedgerLivePath: t.TempDir()})
+	disablePrompt(t)
+
+	const writers = 8
+	configs := make([]*Config, writers)
+	for i := range configs {
+		configs[i] = loadTestConfig(t)
+		configs[i].Parameters = append(configs[i].Parameters, Parameter{ID: NewID(), Name: fmt.Sprintf("Param %d", i), EnvVar: fmt.Sprintf("P%d=1", i)})
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := range configs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = SaveConfigToPath(configs[i], path)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+	saved := loadTestConfig(t)
+	if len(saved.Parameters) != writers {
+		t.Errorf("expected %d parameters after parallel saves, got %+v", writers, saved.Parameters)
+	}
+}
+
+func TestParallelSavesRenameSameNameAdditions(t *testing.T) {
+	path := useTempConfig(t, &Config{LedgerLivePath: t.TempDir()})
+	disablePrompt(t)
+
+	var warnings strings.Builder
+	var warningsMu sync.Mutex
+	Diagnostics = writerFunc(func(p []byte) (int, error) {
+		warningsMu.Lock()
+		defer warningsMu.Unlock()
+		return warnings.Write(p)
+	})
+	plain := func(text string) string { return text }
+	WarningText, NormalText = plain, plain
+	t.Cleanup(func() {
+		Diagnostics = os.Stdout
+		WarningText, NormalText = nil, nil
+	})
+
+	first := loadTestConfig(t)
+	second := loadTestConfig(t)
+	first.Parameters = append(first.Parameters, Parameter{ID: NewID(), Name: "Debug", EnvVar: "DEBUG=1"})
+	second.Parameters = append(second.Parameters, Parameter{ID: NewID(), Name: "Debug", EnvVar: "DEBUG=2"})
+
+	var wg sync.WaitGroup
+	for _, config := range []*Config{first, second} {
+		wg.Add(1)
+		go func(config *Config) {
+			defer wg.Done()
+			if err := SaveConfigToPath(config, path); err != nil {
+				t.Errorf("save: %v", err)
+			}
+		}(config)
+	}
+	wg.Wait()
+
+	saved := loadTestConfig(t)
+	if len(saved.Parameters) != 2 || FindParameter("Debug", saved.Parameters) == nil || FindParameter("Debug (2)", saved.Parameters) == nil {
+		t.Errorf("expected both parameters kept under unique names, got %+v", saved.Parameters)
+	}
+	if !strings.Contains(warnings.String(), "was also added in the other version") {
+		t.Errorf("expected the name collision to be reported, got %q", warnings.String())
+	}
+}
+
+// writerFunc adapts a function to io.Writer
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestMergeEntries(t *testing.T) {
+	id := func(p Parameter) string { return p.ID }
+	name := func(p Parameter) string { return p.Name }
+	rename := func(p Parameter, name string) Parameter { p.Name = name; return p }
+	param := func(id, description string) Parameter {
+		return Parameter{ID: id, Name: "param " + id, EnvVar: "X=1", Description: description}
+	}
+
+	tests := []struct {
+		name      string
+		base      []Parameter
+		mine      []Parameter
+		theirs    []Parameter
+		want      []Parameter
+		conflicts []string
+		renames   []entryRename
+	}{
+		{
+			name:   "added on both sides",
+			base:   []Parameter{param("a", "")},
+			mine:   []Parameter{param("a", ""), param("m", "")},
+			theirs: []Parameter{param("a", ""), param("t", "")},
+			want:   []Parameter{param("a", ""), param("t", ""), param("m", "")},
+		},
+		{
+			name:   "edited by me, deleted by them",
+			base:   []Parameter{param("a", "old")},
+			mine:   []Parameter{param("a", "mine")},
+			theirs: nil,
+			want:   []Parameter{param("a", "mine")},
+		},
+		{
+			name:   "deleted by me, edited by them",
+			base:   []Parameter{param("a", "old")},
+			mine:   nil,
+			theirs: []Parameter{param("a", "theirs")},
+			want:   []Parameter{param("a", "theirs")},
+		},
+		{
+			name:   "deleted by me, untouched by them",
+			base:   []Parameter{param("a", "old"), param("b", "")},
+			mine:   []Parameter{param("b", "")},
+			theirs: []Parameter{param("a", "old"), param("b", "")},
+			want:   []Parameter{param("b", "")},
+		},
+		{
+			name:      "same ID edited on both sides",
+			base:      []Parameter{param("a", "old")},
+			mine:      []Parameter{param("a", "mine")},
+			theirs:    []Parameter{param("a", "theirs")},
+			want:      []Parameter{param("a", "mine")},
+			conflicts: []string{"param a"},
+		},
+		{
+			name:    "same name added on both sides",
+			base:    []Parameter{param("a", "")},
+			mine:    []Parameter{param("a", ""), {ID: "m", Name: "New", EnvVar: "X=1"}},
+			theirs:  []Parameter{param("a", ""), {ID: "t", Name: "New", EnvVar: "X=2"}},
+			want:    []Parameter{param("a", ""), {ID: "t", Name: "New", EnvVar: "X=2"}, {ID: "m", Name: "New (2)", EnvVar: "X=1"}},
+			renames: []entryRename{{From: "New", To: "New (2)"}},
+		},
+		{
+			name:      "same ID added differently on both sides",
+			mine:      []Parameter{param("n", "mine")},
+			theirs:    []Parameter{param("n", "theirs")},
+			want:      []Parameter{param("n", "mine")},
+			conflicts: []string{"param n"},
+		},
+		{
+			name:   "same edit on both sides",
+			base:   []Parameter{param("a", "old")},
+			mine:   []Parameter{param("a", "same")},
+			theirs: []Parameter{param("a", "same")},
+			want:   []Parameter{param("a", "same")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts, renames := mergeEntries(tt.base, tt.mine, tt.theirs, id, name, rename)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("merged = %+v, want %+v", got, tt.want)
+			}
+			if strings.Join(conflicts, ",") != strings.Join(tt.conflicts, ",") {
+				t.Errorf("conflicts = %v, want %v", conflicts, tt.conflicts)
+			}
+			if fmt.Sprint(renames) != fmt.Sprint(tt.renames) {
+				t.Errorf("renames = %v, want %v", renames, tt.renames)
+			}
+		})
+	}
+}
+
+func TestWithFileLockSerialisesHolders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	held := make(chan struct{})
+	release := make(chan struct{})
+	var order []string
+	var mu sync.Mutex
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, event)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := WithFileLock(path, func() error {
+			close(held)
+			<-release
+			record("first done")
+			return nil
+		})
+		if err != nil {
+			t.Errorf("first holder: %v", err)
+		}
+	}()
+	<-held
+
+	// A second holder waits for the first one to release the lock
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := WithFileLock(path, func() error {
+			record("second ran")
+			return nil
+		})
+		if err != nil {
+			t.Errorf("second holder: %v", err)
+		}
+	}()
+	time.Sleep(3 * lockRetryInterval)
+	record("released")
+	close(release)
+	wg.Wait()
+
+	if want := "released,first done,second ran"; strings.Join(order, ",") != want {
+		t.Errorf("order = %v, want %s", order, want)
+	}
+}
+
+func TestWithFileLockTimesOut(t *testing.T) {
+	defer func(timeout time.Duration) { lockTimeout = timeout }(lockTimeout)
+	lockTimeout = 4 * lockRetryInterval
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	held := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		WithFileLock(path, func() error {
+			close(held)
+			<-release
+			return nil
+		})
+	}()
+	<-held
+	defer func() {
+		close(release)
+		<-done
+	}()
+
+	ran := false
+	start := time.Now()
+	err := WithFileLock(path, func() error {
+		ran = true
+		return nil
+	})
+	if err == nil || ran {
+		t.Fatalf("expected the second holder to time out, ran=%v err=%v", ran, err)
+	}
+	if !strings.Contains(err.Error(), "locked by another ledger-live instance") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < lockTimeout {
+		t.Errorf("gave up after %v, before the %v timeout", elapsed, lockTimeout)
+	}
+	if _, err := os.Stat(path + ".lock"); err != nil {
+		t.Errorf("lock file missing: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package setup
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,21 +10,92 @@ import (
 
 // Config structures
 type Config struct {
-	LedgerLivePath string      `json:"ledger-live-path"`
-	Parameters     []Parameter `json:"parameters"`
-	Presets        []Preset    `json:"presets,omitempty"`
+	Schema         string      `json:"$schema,omitempty" yaml:"$schema,omitempty" toml:"$schema,omitempty"` // JSON Schema reference for editors
+	SchemaVersion  int         `json:"schema_version" yaml:"schema_version" toml:"schema_version"`
+	LedgerLivePath string      `json:"ledger-live-path" yaml:"ledger-live-path" toml:"ledger-live-path"`
+	Parameters     []Parameter `json:"parameters" yaml:"parameters" toml:"parameters"`
+	Presets        []Preset    `json:"presets,omitempty" yaml:"presets,omitempty" toml:"presets,omitempty"`
+	UpdateCheck    *bool       `json:"update_check,omitempty" yaml:"update_check,omitempty" toml:"update_check,omitempty"`          // Check for new starter versions on start, default true
+	UpdateEndpoint string      `json:"update_endpoint,omitempty" yaml:"update_endpoint,omitempty" toml:"update_endpoint,omitempty"` // Releases API of the starter repository, e.g. a local test server
+	UpdateChannel  string      `json:"update_channel,omitempty" yaml:"update_channel,omitempty" toml:"update_channel,omitempty"`    // "stable" or "beta" releases of the starter, default stable
+	Theme          *Theme      `json:"theme,omitempty" yaml:"theme,omitempty" toml:"theme,omitempty"`                               // Interface colors, default the built-in default theme
+
+	loadedPath string        // File the config was loaded from or last saved to
+	loadedData []byte        // Its contents at that time, to detect changes by other instances
+	layers     *configLayers // Repository and environment layers merged on load
 }
 
 type Parameter struct {
-	Name        string `json:"name"`
-	EnvVar      string `json:"env_var"`
-	Description string `json:"description"`
+	ID          string   `json:"id,omitempty" yaml:"id,omitempty" toml:"id,omitempty"` // Stable identifier, kept across renames
+	Name        string   `json:"name" yaml:"name" toml:"name"`
+	EnvVar      string   `json:"env_var" yaml:"env_var" toml:"env_var"`
+	Description string   `json:"description" yaml:"description" toml:"description"`
+	Platforms   []string `json:"platforms,omitempty" yaml:"platforms,omitempty" toml:"platforms,omitempty"` // Platforms this applies to, empty means all
+	Requires    []string `json:"requires,omitempty" yaml:"requires,omitempty" toml:"requires,omitempty"`    // IDs of parameters that must be selected too
+	Conflicts   []string `json:"conflicts,omitempty" yaml:"conflicts,omitempty" toml:"conflicts,omitempty"` // IDs of parameters that cannot be selected together
+	Category    string   `json:"category,omitempty" yaml:"category,omitempty" toml:"category,omitempty"`    // Group shown in parameter selection
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty"`                // Extra search keywords
+	Secret      bool     `json:"secret,omitempty" yaml:"secret,omitempty" toml:"secret,omitempty"`          // Value is kept in the encrypted vault, EnvVar holds only the name
 }
 
 type Preset struct {
-	Name       string   `json:"name"`
-	Platform   string   `json:"platform"`    // "mobile" or "desktop"
-	Parameters []string `json:"parameters"`  // List of parameter names
+	ID               string            `json:"id,omitempty" yaml:"id,omitempty" toml:"id,omitempty"` // Stable identifier, kept across renames
+	Name             string            `json:"name" yaml:"name" toml:"name"`
+	Extends          string            `json:"extends,omitempty" yaml:"extends,omitempty" toml:"extends,omitempty"`                               // ID of the base preset to inherit from
+	Platform         string            `json:"platform" yaml:"platform" toml:"platform"`                                                          // "mobile" or "desktop", empty inherits from the base
+	Parameters       []string          `json:"parameters" yaml:"parameters" toml:"parameters"`                                                    // IDs of the parameters to set (added to inherited ones)
+	RemoveParameters []string          `json:"remove_parameters,omitempty" yaml:"remove_parameters,omitempty" toml:"remove_parameters,omitempty"` // IDs of inherited parameters to drop
+	Favorite         bool              `json:"favorite,omitempty" yaml:"favorite,omitempty" toml:"favorite,omitempty"`                            // Pinned to the top of the start menu
+	Group            string            `json:"group,omitempty" yaml:"group,omitempty" toml:"group,omitempty"`                                     // Start menu section, empty for top level
+	Env              map[string]string `json:"env,omitempty" yaml:"env,omitempty" toml:"env,omitempty"`                                           // Env overrides applied after parameters
+	Note             string            `json:"note,omitempty" yaml:"note,omitempty" toml:"note,omitempty"`                                        // Free text shown in the start menu
+	WorkDir          string            `json:"work_dir,omitempty" yaml:"work_dir,omitempty" toml:"work_dir,omitempty"`                            // Subdirectory of the Ledger Live path to run in
+	Args             []string          `json:"args,omitempty" yaml:"args,omitempty" toml:"args,omitempty"`                                        // Extra arguments appended to the command
+}
+
+// UpdateCheckEnabled reports whether the update check is on, which is the default
+func (c *Config) UpdateCheckEnabled() bool {
+	return c.UpdateCheck == nil || *c.UpdateCheck
+}
+
+// Theme selects a built-in theme and overrides some of its colors
+type Theme struct {
+	Name   string                `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`       // Built-in theme to start from, see ThemeNames
+	Colors map[string]ThemeColor `json:"colors,omitempty" yaml:"colors,omitempty" toml:"colors,omitempty"` // Overrides by role, see ThemeColorRoles
+}
+
+// ThemeColor is a hex color for light and dark terminal backgrounds. When only one is
+// set it is used for both.
+type ThemeColor struct {
+	Light string `json:"light,omitempty" yaml:"light,omitempty" toml:"light,omitempty"`
+	Dark  string `json:"dark,omitempty" yaml:"dark,omitempty" toml:"dark,omitempty"`
+}
+
+// Built-in themes
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+)
+
+// ThemeNames lists the built-in themes
+var ThemeNames = []string{ThemeDefault, ThemeHighContrast, ThemeMonochrome}
+
+// ThemeColorRoles lists the colors a theme can override
+var ThemeColorRoles = []string{"primary", "muted", "text", "highlight", "error", "success", "info", "warning", "gradient_start", "gradient_end"}
+
+// Release channels of the starter
+const (
+	ChannelStable = "stable" // Releases only
+	ChannelBeta   = "beta"   // Releases and pre-releases
+)
+
+// UpdateChannelOrDefault returns the configured release channel, stable if unset
+func (c *Config) UpdateChannelOrDefault() string {
+	if c.UpdateChannel == "" {
+		return ChannelStable
+	}
+	return c.UpdateChannel
 }
 
 // Config path management functions
@@ -33,6 +105,11 @@ func SetConfigPath(path string) {
 	configPath = path
 }
 
+// ConfigPathIsExplicit reports whether --config or LEDGER_LIVE_STARTER_CONFIG picks the config file
+func ConfigPathIsExplicit() bool {
+	return configPath != "" || os.Getenv("LEDGER_LIVE_STARTER_CONFIG") != ""
+}
+
 func GetConfigPath() string {
 	// Use flag value if provided
 	if configPath != "" {
@@ -44,21 +121,24 @@ func GetConfigPath() string {
 		return customPath
 	}
 	
-	// Use ~/.ledger-live/config.json (next to binary)
-	return getLedgerLiveConfigPath()
+	// Use the active profile, ~/.ledger-live/config.json (next to binary) by default
+	return GetProfileConfigPath(ActiveProfile())
 }
 
-func getLedgerLiveConfigPath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		// Fallback to current directory
-		return "config.json"
+// configFileInDir returns the config file in a directory, config.json if there is none yet
+func configFileInDir(dir string) string {
+	// Use an existing YAML or TOML config if there is no JSON one
+	for _, name := range configFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
 	}
-	
-	return filepath.Join(homeDir, ".ledger-live", "config.json")
+	return filepath.Join(dir, "config.json")
 }
 
-func getLedgerLiveDir() string {
+// GetLedgerLiveDir returns ~/.ledger-live, which holds the binary, the default profile and shared state
+func GetLedgerLiveDir() string {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "."
@@ -68,7 +148,7 @@ func getLedgerLiveDir() string {
 }
 
 func EnsureConfigDirExists() error {
-	return os.MkdirAll(getLedgerLiveDir(), 0755)
+	return os.MkdirAll(GetLedgerLiveDir(), 0755)
 }
 
 func ConfigExists() bool {
@@ -77,18 +157,86 @@ func ConfigExists() bool {
 }
 
 func SaveConfigToPath(config *Config, path string) error {
-	data, err := json.MarshalIndent(config, "", "  ")
+	// Values from the repository config and environment are not copied into the global file
+	stored := config
+	layers := config.layers
+	if layers != nil && path == layers.globalPath {
+		stored = layers.globalView(config)
+	}
+
+	// Reapply the other layers to the saved values so the caller keeps seeing the full config
+	adopt := func() {
+		if stored != config {
+			*config = *stored
+			config.layers = layers
+			layers.apply(config)
+		}
+	}
+
+	// Another instance may have saved since this config was loaded
+	changedOnDisk := func() ([]byte, bool) {
+		current, err := os.ReadFile(path)
+		return current, err == nil && stored.loadedPath == path && !bytes.Equal(current, stored.loadedData)
+	}
+
+	for {
+		// Ask how to handle a change before locking, so the prompt never blocks other instances
+		if current, changed := changedOnDisk(); changed {
+			write, err := resolveDiskChange(stored, path, current)
+			if err != nil {
+				adopt()
+				return err
+			}
+			if !write {
+				return nil
+			}
+		}
+
+		changedAgain := false
+		err := WithFileLock(path, func() error {
+			current, changed := changedOnDisk()
+			if changed {
+				// Saved again since the prompt, resolve that change outside the lock too
+				changedAgain = true
+				return nil
+			}
+			return writeConfigLocked(stored, path, current, adopt)
+		})
+		if err != nil || !changedAgain {
+			return err
+		}
+	}
+}
+
+// writeConfigLocked writes a config while the caller holds the config file lock
+func writeConfigLocked(stored *Config, path string, current []byte, adopt func()) error {
+	// New parameters and presets get their IDs on first save
+	EnsureIDs(stored)
+	stored.SchemaVersion = CurrentSchemaVersion
+
+	data, err := encodeConfig(stored, FormatForPath(path), current)
 	if err != nil {
 		return err
 	}
-	
-	return os.WriteFile(path, data, 0644)
+
+	// Keep the previous version so the change can be undone
+	if err := backupConfigFile(path, data); err != nil {
+		return fmt.Errorf("failed to back up config: %v", err)
+	}
+
+	if err := WriteFileAtomic(path, data, 0644); err != nil {
+		return err
+	}
+	stored.loadedPath = path
+	stored.loadedData = data
+	adopt()
+	return nil
 }
 
 func LoadConfig() (*Config, error) {
 	// Check if config exists, trigger setup if not
 	if !ConfigExists() {
-		fmt.Printf("%s %s\n\n", TitleText("Setup:"), NormalText("No configuration found. Running setup mode..."))
+		fmt.Fprintf(Diagnostics, "%s %s\n\n", TitleText("Setup:"), NormalText("No configuration found. Running setup mode..."))
 		return RunSetupMode()
 	}
 	
@@ -98,23 +246,85 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file %s: %v", configFilePath, err)
 	}
 
-	var config Config
-	err = json.Unmarshal(data, &config)
+	// Upgrade configs written by older versions, keeping a backup of the original
+	migratedData, fromVersion, migrated, err := migrateConfigData(data, configFilePath)
+	if err != nil {
+		if _, ok := err.(*ErrNewerSchema); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to parse config file: %v", err)
+	}
+
+	config, err := decodeMigratedConfig(migratedData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %v", err)
 	}
 
+	config.loadedPath = configFilePath
+	config.loadedData = data
+
+	if migrated {
+		backupPath, err := writeMigrationBackup(configFilePath, data, fromVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up config file before migration: %v", err)
+		}
+		if err := SaveConfigToPath(config, configFilePath); err != nil {
+			return nil, fmt.Errorf("failed to save migrated config file: %v", err)
+		}
+		fmt.Fprintf(Diagnostics, "%s %s %s\n\n", InfoTextTitle("Info:"), NormalText(fmt.Sprintf("Config upgraded from schema version %d to %d, backup saved to", fromVersion, CurrentSchemaVersion)), HighlightText(backupPath))
+	}
+
+	// Merge the team config from the repository and environment overrides
+	if err := applyConfigLayers(config, configFilePath); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// PeekConfig reads the config without running setup, migrating the file or printing anything.
+// It is meant for shell completion, where nothing may be written to the terminal.
+func PeekConfig() (*Config, error) {
+	configFilePath := GetConfigPath()
+	data, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	migratedData, _, _, err := migrateConfigData(data, configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := decodeMigratedConfig(migratedData)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyConfigLayers(config, configFilePath); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// decodeMigratedConfig decodes migrated config JSON, converting references written as names to IDs
+func decodeMigratedConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	ResolveReferenceNames(&config)
 	return &config, nil
 }
 
 func GetDefaultConfig() *Config {
-	return &Config{
+	config := &Config{
+		SchemaVersion:  CurrentSchemaVersion,
 		LedgerLivePath: "",
 		Parameters: []Parameter{
 			{
 				Name:        "Skip onboarding",
 				EnvVar:      "SKIP_ONBOARDING=1",
 				Description: "Enable skipping the onboarding process on mobile",
+				Platforms:   []string{"mobile"},
 			},
 			{
 				Name:        "Disable transaction broadcast",
@@ -129,6 +339,8 @@ func GetDefaultConfig() *Config {
 		},
 		Presets: []Preset{},
 	}
+	EnsureIDs(config)
+	return config
 }
 
 func SaveConfig(config *Config) error {
@@ -0,0 +1,278 @@
+package setup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFormat is a config file format, selected by file extension
+type ConfigFormat string
+
+const (
+	FormatJSON ConfigFormat = "json"
+	FormatYAML ConfigFormat = "yaml"
+	FormatTOML ConfigFormat = "toml"
+)
+
+// ConfigFormats lists the supported formats
+var ConfigFormats = []ConfigFormat{FormatJSON, FormatYAML, FormatTOML}
+
+// configFileNames are the default config file names, in order of preference
+var configFileNames = []string{"config.json", "config.yaml", "config.yml", "config.toml"}
+
+// FormatForPath returns the format of a config file from its extension, defaulting to JSON
+func FormatForPath(path string) ConfigFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatJSON
+	}
+}
+
+// ParseConfigFormat parses a format name such as "yaml" or "yml"
+func ParseConfigFormat(name string) (ConfigFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	}
+	return "", fmt.Errorf("unknown format '%s' (expected json, yaml or toml)", name)
+}
+
+// Extension returns the file extension for a format
+func (f ConfigFormat) Extension() string {
+	return "." + string(f)
+}
+
+// configDocumentToJSON converts config file contents to JSON, so migrations and
+// decoding work the same for every format
+func configDocumentToJSON(data []byte, format ConfigFormat) ([]byte, error) {
+	var document map[string]any
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		if document == nil {
+			document = map[string]any{}
+		}
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+	default:
+		return data, nil
+	}
+	return json.Marshal(document)
+}
+
+// decodeConfig parses config file contents of any format without migrating them
+func decodeConfig(data []byte, format ConfigFormat) (*Config, error) {
+	jsonData, err := configDocumentToJSON(data, format)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := json.Unmarshal(jsonData, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// encodeConfig serializes a config. The previous file contents, if any, are used to
+// keep comments: YAML comments stay attached to their keys and entries, TOML keeps
+// the comment block at the top of the file. JSON has no comments.
+func encodeConfig(config *Config, format ConfigFormat, previous []byte) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return encodeYAML(config, previous)
+	case FormatTOML:
+		return encodeTOML(config, previous)
+	default:
+		return json.MarshalIndent(config, "", "  ")
+	}
+}
+
+func encodeYAML(config *Config, previous []byte) ([]byte, error) {
+	var document yaml.Node
+	if err := document.Encode(config); err != nil {
+		return nil, err
+	}
+
+	if len(previous) > 0 {
+		var old yaml.Node
+		if yaml.Unmarshal(previous, &old) == nil && len(old.Content) > 0 {
+			document.HeadComment = old.HeadComment
+			document.FootComment = old.FootComment
+			copyYAMLComments(old.Content[0], &document)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// copyYAMLComments copies comments from an old node tree onto the matching nodes of a new one.
+// Mapping entries match by key, sequence entries by their id or name, then by position.
+func copyYAMLComments(old *yaml.Node, node *yaml.Node) {
+	if old == nil || node == nil {
+		return
+	}
+	node.HeadComment = preferComment(node.HeadComment, old.HeadComment)
+	node.LineComment = preferComment(node.LineComment, old.LineComment)
+	node.FootComment = preferComment(node.FootComment, old.FootComment)
+
+	if old.Kind != node.Kind {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			for j := 0; j+1 < len(old.Content); j += 2 {
+				if old.Content[j].Value == node.Content[i].Value {
+					copyYAMLComments(old.Content[j], node.Content[i])
+					copyYAMLComments(old.Content[j+1], node.Content[i+1])
+					break
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			if match := matchYAMLEntry(old, item, i); match != nil {
+				copyYAMLComments(match, item)
+			}
+		}
+	}
+}
+
+// matchYAMLEntry finds the old sequence entry corresponding to a new one
+func matchYAMLEntry(old *yaml.Node, item *yaml.Node, index int) *yaml.Node {
+	for _, key := range []string{"id", "name"} {
+		value := yamlMappingValue(item, key)
+		if value == "" {
+			continue
+		}
+		for _, candidate := range old.Content {
+			if yamlMappingValue(candidate, key) == value {
+				return candidate
+			}
+		}
+	}
+	if item.Kind == yaml.ScalarNode {
+		for _, candidate := range old.Content {
+			if candidate.Kind == yaml.ScalarNode && candidate.Value == item.Value {
+				return candidate
+			}
+		}
+		return nil
+	}
+	if index < len(old.Content) && yamlMappingValue(item, "id") == "" && yamlMappingValue(item, "name") == "" {
+		return old.Content[index]
+	}
+	return nil
+}
+
+func yamlMappingValue(node *yaml.Node, key string) string {
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// preferComment keeps a node's own comment, falling back to the previous one
+func preferComment(current string, previous string) string {
+	if current == "" {
+		return previous
+	}
+	return current
+}
+
+func encodeTOML(config *Config, previous []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(tomlHeaderComment(previous))
+
+	encoder := toml.NewEncoder(&buf)
+	encoder.Indent = ""
+	if err := encoder.Encode(config); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// tomlHeaderComment returns the comment lines at the top of a TOML file
+func tomlHeaderComment(data []byte) string {
+	var header strings.Builder
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		header.WriteString(line)
+	}
+	if header.Len() > 0 {
+		header.WriteString("\n")
+	}
+	return header.String()
+}
+
+// ConvertConfigFile writes the config at path in another format next to it and moves the
+// original aside to path + ".bak". It returns the path of the converted file.
+func ConvertConfigFile(path string, format ConfigFormat) (string, error) {
+	if FormatForPath(path) == format {
+		return "", fmt.Errorf("%s is already in %s format", path, format)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+	config, err := parseConfigData(data, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse config file: %v", err)
+	}
+	config.SchemaVersion = CurrentSchemaVersion
+
+	newPath := strings.TrimSuffix(path, filepath.Ext(path)) + format.Extension()
+	if _, err := os.Stat(newPath); err == nil {
+		return "", fmt.Errorf("%s already exists", newPath)
+	}
+
+	converted, err := encodeConfig(config, format, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := WriteFileAtomic(newPath, converted, 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(path, path+".bak"); err != nil {
+		return "", fmt.Errorf("converted to %s but could not move the original aside: %v", newPath, err)
+	}
+	return newPath, nil
+}
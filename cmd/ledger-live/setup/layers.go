@@ -0,0 +1,256 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RepoConfigFileName is the team config committed at the root of the Ledger Live repository
+const RepoConfigFileName = ".ledger-live-starter.json"
+
+// Prefix of the environment variables overriding config settings
+const envPrefix = "LEDGER_LIVE_STARTER_"
+
+// envNoRepoConfig turns off the repository layer
+const envNoRepoConfig = envPrefix + "NO_REPO_CONFIG"
+
+// envSetting is a top-level config value that an environment variable can override
+type envSetting struct {
+	key string
+	get func(config *Config) string
+	set func(config *Config, value string) error
+}
+
+// envSettings are the settings of the environment layer, each overridden by
+// LEDGER_LIVE_STARTER_ followed by its key in upper case, e.g. LEDGER_LIVE_STARTER_UPDATE_CHANNEL
+var envSettings = []envSetting{
+	{
+		key: "ledger-live-path",
+		get: func(c *Config) string { return c.LedgerLivePath },
+		set: func(c *Config, value string) error { c.LedgerLivePath = value; return nil },
+	},
+	{
+		key: "update_check",
+		get: func(c *Config) string {
+			if c.UpdateCheck == nil {
+				return ""
+			}
+			return strconv.FormatBool(*c.UpdateCheck)
+		},
+		set: func(c *Config, value string) error {
+			if value == "" {
+				c.UpdateCheck = nil
+				return nil
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("must be true or false")
+			}
+			c.UpdateCheck = &enabled
+			return nil
+		},
+	},
+	{
+		key: "update_endpoint",
+		get: func(c *Config) string { return c.UpdateEndpoint },
+		set: func(c *Config, value string) error { c.UpdateEndpoint = value; return nil },
+	},
+	{
+		key: "update_channel",
+		get: func(c *Config) string { return c.UpdateChannel },
+		set: func(c *Config, value string) error { c.UpdateChannel = value; return nil },
+	},
+}
+
+// SettingEnvVar returns the environment variable overriding a config setting
+func SettingEnvVar(key string) string {
+	return envPrefix + strings.ToUpper(strings.NewReplacer("-", "_").Replace(key))
+}
+
+// Layer names used when reporting where a value came from
+const (
+	LayerRepository = "repository"
+	LayerGlobal     = "global"
+	LayerEnv        = "env"
+)
+
+// ValueOrigin tells which layer a config value came from
+type ValueOrigin struct {
+	Key    string // e.g. ledger-live-path, parameters.Debug mode, presets.QA
+	Layer  string
+	Source string // File path or environment variable
+	Value  string // Value of a setting, empty for parameters and presets
+}
+
+// configLayers keeps the layers merged into a loaded config, so saving writes only the
+// user's own values back to the global file
+type configLayers struct {
+	globalPath string
+	global     map[string]string // Settings of the global file, by key
+	env        map[string]string // Settings overridden by environment variables, by key
+	repoPath   string
+	repo       *Config
+	origins    []ValueOrigin
+}
+
+// applyConfigLayers merges the repository config and environment overrides into a config
+// loaded from the global file. Precedence, lowest first: repository file, global file,
+// environment variables. Parameters and presets are matched by name, and repository
+// entries also defined globally take the global entry's ID.
+func applyConfigLayers(config *Config, globalPath string) error {
+	layers := &configLayers{
+		globalPath: globalPath,
+		global:     make(map[string]string),
+		env:        make(map[string]string),
+	}
+	for _, setting := range envSettings {
+		if value := os.Getenv(SettingEnvVar(setting.key)); value != "" {
+			// Keep the value as the config holds it, e.g. "1" as "true"
+			var parsed Config
+			if err := setting.set(&parsed, value); err != nil {
+				return fmt.Errorf("invalid %s: %v", SettingEnvVar(setting.key), err)
+			}
+			layers.env[setting.key] = setting.get(&parsed)
+		}
+	}
+
+	// The repository file lives in the Ledger Live checkout, so resolve the path first
+	ledgerLivePath := config.LedgerLivePath
+	if value, ok := layers.env["ledger-live-path"]; ok {
+		ledgerLivePath = value
+	}
+	if ledgerLivePath != "" && os.Getenv(envNoRepoConfig) == "" {
+		repoPath := filepath.Join(ledgerLivePath, RepoConfigFileName)
+		if data, err := os.ReadFile(repoPath); err == nil {
+			repo, err := parseConfigData(data, repoPath)
+			if err != nil {
+				return fmt.Errorf("failed to parse repository config %s: %v", repoPath, err)
+			}
+			alignRepoIDs(repo, config)
+			layers.repoPath = repoPath
+			layers.repo = repo
+		}
+	}
+
+	config.layers = layers
+	layers.apply(config)
+	return nil
+}
+
+// alignRepoIDs gives repository entries that the global file also defines the global
+// entry's ID, and converts repository references written as names, which may name
+// entries of either file
+func alignRepoIDs(repo *Config, global *Config) {
+	for i, param := range repo.Parameters {
+		if existing := FindParameter(param.Name, global.Parameters); existing != nil && existing.ID != param.ID {
+			ReplaceParameterReferences([]string{param.ID}, existing.ID, repo)
+			repo.Parameters[i].ID = existing.ID
+		}
+	}
+	for i, preset := range repo.Presets {
+		if existing := FindPreset(preset.Name, global.Presets); existing != nil && existing.ID != preset.ID {
+			for j := range repo.Presets {
+				if repo.Presets[j].Extends == preset.ID {
+					repo.Presets[j].Extends = existing.ID
+				}
+			}
+			repo.Presets[i].ID = existing.ID
+		}
+	}
+
+	combined := &Config{
+		Parameters: append(append([]Parameter{}, global.Parameters...), repo.Parameters...),
+		Presets:    append(append([]Preset{}, global.Presets...), repo.Presets...),
+	}
+	resolveReferenceNames(repo, combined)
+}
+
+// apply merges the non-global layers into a config holding the global file's values
+func (l *configLayers) apply(config *Config) {
+	l.origins = nil
+	for _, setting := range envSettings {
+		l.global[setting.key] = setting.get(config)
+		if value, ok := l.env[setting.key]; ok {
+			setting.set(config, value)
+			l.origins = append(l.origins, ValueOrigin{Key: setting.key, Layer: LayerEnv, Source: SettingEnvVar(setting.key), Value: value})
+		} else if value := setting.get(config); value != "" || setting.key == "ledger-live-path" {
+			l.origins = append(l.origins, ValueOrigin{Key: setting.key, Layer: LayerGlobal, Source: l.globalPath, Value: value})
+		}
+	}
+
+	for _, param := range config.Parameters {
+		l.origins = append(l.origins, ValueOrigin{Key: "parameters." + param.Name, Layer: LayerGlobal, Source: l.globalPath})
+	}
+	for _, preset := range config.Presets {
+		l.origins = append(l.origins, ValueOrigin{Key: "presets." + preset.Name, Layer: LayerGlobal, Source: l.globalPath})
+	}
+
+	if l.repo == nil {
+		return
+	}
+	for _, param := range l.repo.Parameters {
+		if FindParameter(param.Name, config.Parameters) == nil {
+			config.Parameters = append(config.Parameters, param)
+			l.origins = append(l.origins, ValueOrigin{Key: "parameters." + param.Name, Layer: LayerRepository, Source: l.repoPath})
+		}
+	}
+	for _, preset := range l.repo.Presets {
+		if FindPreset(preset.Name, config.Presets) == nil {
+			config.Presets = append(config.Presets, preset)
+			l.origins = append(l.origins, ValueOrigin{Key: "presets." + preset.Name, Layer: LayerRepository, Source: l.repoPath})
+		}
+	}
+
+	// Global entries may refer to repository entries by name
+	resolveReferenceNames(config, config)
+}
+
+// globalView returns the part of a layered config that belongs in the global file:
+// unchanged repository entries and environment overrides are left out
+func (l *configLayers) globalView(config *Config) *Config {
+	view := *config
+	for _, setting := range envSettings {
+		if value, ok := l.env[setting.key]; ok && setting.get(config) == value {
+			setting.set(&view, l.global[setting.key])
+		}
+	}
+	if l.repo == nil {
+		return &view
+	}
+
+	view.Parameters = nil
+	for _, param := range config.Parameters {
+		if repoParam := FindParameter(param.Name, l.repo.Parameters); repoParam != nil && reflect.DeepEqual(*repoParam, param) {
+			continue
+		}
+		view.Parameters = append(view.Parameters, param)
+	}
+	view.Presets = nil
+	for _, preset := range config.Presets {
+		if repoPreset := FindPreset(preset.Name, l.repo.Presets); repoPreset != nil && reflect.DeepEqual(*repoPreset, preset) {
+			continue
+		}
+		view.Presets = append(view.Presets, preset)
+	}
+	return &view
+}
+
+// ConfigOrigins lists which layer each top-level value of a loaded config came from
+func ConfigOrigins(config *Config) []ValueOrigin {
+	if config.layers == nil {
+		return nil
+	}
+	return config.layers.origins
+}
+
+// RepoConfigPath returns the repository config merged into a loaded config, or ""
+func RepoConfigPath(config *Config) string {
+	if config.layers == nil {
+		return ""
+	}
+	return config.layers.repoPath
+}
@@ -0,0 +1,64 @@
+package setup
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnvLayerOverridesSettings(t *testing.T) {
+	path := useTempConfig(t, &Config{LedgerLivePath: t.TempDir(), UpdateChannel: ChannelStable})
+	t.Setenv(SettingEnvVar("update_channel"), ChannelBeta)
+	t.Setenv(SettingEnvVar("update_check"), "0")
+
+	config := loadTestConfig(t)
+	if config.UpdateChannel != ChannelBeta || config.UpdateCheck == nil || *config.UpdateCheck {
+		t.Fatalf("environment overrides not applied: channel %q, update check %v", config.UpdateChannel, config.UpdateCheck)
+	}
+
+	origins := make(map[string]ValueOrigin)
+	for _, origin := range ConfigOrigins(config) {
+		origins[origin.Key] = origin
+	}
+	if origin := origins["update_channel"]; origin.Layer != LayerEnv || origin.Source != "LEDGER_LIVE_STARTER_UPDATE_CHANNEL" || origin.Value != ChannelBeta {
+		t.Errorf("update_channel origin = %+v", origin)
+	}
+	if origin := origins["update_check"]; origin.Layer != LayerEnv || origin.Value != "false" {
+		t.Errorf("update_check origin = %+v", origin)
+	}
+	if origin := origins["ledger-live-path"]; origin.Layer != LayerGlobal || origin.Source != path {
+		t.Errorf("ledger-live-path origin = %+v", origin)
+	}
+
+	// Saving keeps the environment values out of the global file
+	config.Parameters = append(config.Parameters, Parameter{ID: NewID(), Name: "Debug", EnvVar: "DEBUG=1"})
+	if err := SaveConfigToPath(config, path); err != nil {
+		t.Fatal(err)
+	}
+	os.Unsetenv(SettingEnvVar("update_channel"))
+	os.Unsetenv(SettingEnvVar("update_check"))
+	saved := loadTestConfig(t)
+	if saved.UpdateChannel != ChannelStable || saved.UpdateCheck != nil {
+		t.Errorf("environment overrides were saved: channel %q, update check %v", saved.UpdateChannel, saved.UpdateCheck)
+	}
+	if FindParameter("Debug", saved.Parameters) == nil {
+		t.Errorf("the edit was not saved: %+v", saved.Parameters)
+	}
+}
+
+func TestEnvLayerRejectsInvalidValues(t *testing.T) {
+	useTempConfig(t, &Config{LedgerLivePath: t.TempDir()})
+	t.Setenv(SettingEnvVar("update_check"), "sometimes")
+
+	if _, err := LoadConfig(); err == nil {
+		t.Error("expected an invalid LEDGER_LIVE_STARTER_UPDATE_CHECK to be reported")
+	}
+}
+
+func TestSettingEnvVar(t *testing.T) {
+	if got := SettingEnvVar("ledger-live-path"); got != "LEDGER_LIVE_STARTER_LEDGER_LIVE_PATH" {
+		t.Errorf("SettingEnvVar(ledger-live-path) = %s", got)
+	}
+	if got := SettingEnvVar("update_endpoint"); got != "LEDGER_LIVE_STARTER_UPDATE_ENDPOINT" {
+		t.Errorf("SettingEnvVar(update_endpoint) = %s", got)
+	}
+}
@@ -0,0 +1,46 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// How long to wait for another instance to release a lock, a variable so tests can shorten it
+var lockTimeout = 10 * time.Second
+
+// How often to retry a held lock
+const lockRetryInterval = 50 * time.Millisecond
+
+// WithFileLock runs fn while holding an advisory lock on path + ".lock", so
+// read-modify-write cycles of different instances do not interleave
+func WithFileLock(path string, fn func() error) error {
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file %s: %v", lockPath, err)
+	}
+	defer file.Close()
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to lock %s: %v", lockPath, err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s is locked by another ledger-live instance", path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+	defer unlockFile(file)
+
+	return fn()
+}
@@ -0,0 +1,186 @@
+package setup
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CurrentSchemaVersion is the config schema version written by this build
+const CurrentSchemaVersion = 3
+
+// Configs without a schema_version predate versioning
+const legacySchemaVersion = 1
+
+// migration upgrades a raw config document from one schema version to the next
+type migration struct {
+	from        int
+	description string
+	apply       func(raw map[string]any) error
+}
+
+// migrations is the upgrade chain, one entry per schema version
+var migrations = []migration{
+	{from: 1, description: "assign stable IDs to parameters and presets", apply: migrateAssignIDs},
+	{from: 2, description: "reference parameters and presets by ID", apply: migrateReferencesToIDs},
+}
+
+// ErrNewerSchema is returned for configs written by a newer version of the tool
+type ErrNewerSchema struct {
+	Path    string
+	Version int
+}
+
+func (e *ErrNewerSchema) Error() string {
+	return fmt.Sprintf("config file %s uses schema version %d, but this version of ledger-live only supports up to %d; please update ledger-live", e.Path, e.Version, CurrentSchemaVersion)
+}
+
+// migrateConfigData upgrades config file contents to the current schema. It returns the
+// config as JSON, the version it started from and whether it was migrated.
+func migrateConfigData(data []byte, path string) ([]byte, int, bool, error) {
+	data, err := configDocumentToJSON(data, FormatForPath(path))
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, false, err
+	}
+
+	version := legacySchemaVersion
+	if value, ok := raw["schema_version"]; ok {
+		number, ok := value.(float64)
+		if !ok || number < 1 || number != float64(int(number)) {
+			return nil, 0, false, fmt.Errorf("invalid schema_version %v", value)
+		}
+		version = int(number)
+	}
+
+	if version > CurrentSchemaVersion {
+		return nil, version, false, &ErrNewerSchema{Path: path, Version: version}
+	}
+	if version == CurrentSchemaVersion {
+		return data, version, false, nil
+	}
+
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		if err := m.apply(raw); err != nil {
+			return nil, version, false, fmt.Errorf("migration from schema version %d (%s) failed: %v", m.from, m.description, err)
+		}
+	}
+	raw["schema_version"] = CurrentSchemaVersion
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, version, false, err
+	}
+	return migrated, version, true, nil
+}
+
+// writeMigrationBackup stores the original config next to it before it is upgraded
+func writeMigrationBackup(path string, data []byte, version int) (string, error) {
+	backupPath := fmt.Sprintf("%s.v%d-%s.bak", path, version, time.Now().Format("20060102-150405"))
+	if err := WriteFileAtomic(backupPath, data, 0644); err != nil {
+		return "", err
+	}
+	return backupPath, nil
+}
+
+// migrateAssignIDs gives every parameter and preset a stable ID
+func migrateAssignIDs(raw map[string]any) error {
+	for key, kind := range map[string]string{"parameters": "parameter", "presets": "preset"} {
+		entries, err := rawEntries(raw, key)
+		if err != nil {
+			return err
+		}
+		taken := make(map[string]bool)
+		for _, entry := range entries {
+			if id, _ := entry["id"].(string); id != "" {
+				taken[id] = true
+			}
+		}
+		for _, entry := range entries {
+			if id, _ := entry["id"].(string); id == "" {
+				name, _ := entry["name"].(string)
+				entry["id"] = assignID(kind, name, taken)
+			}
+		}
+	}
+	return nil
+}
+
+// migrateReferencesToIDs rewrites preset and parameter references from names to IDs.
+// Names that match no entry are kept so validation can still report them.
+func migrateReferencesToIDs(raw map[string]any) error {
+	if err := migrateAssignIDs(raw); err != nil {
+		return err
+	}
+	params, err := rawEntries(raw, "parameters")
+	if err != nil {
+		return err
+	}
+	presets, err := rawEntries(raw, "presets")
+	if err != nil {
+		return err
+	}
+	paramIDs := rawIDsByName(params)
+	presetIDs := rawIDsByName(presets)
+
+	for _, param := range params {
+		rewriteRawReferences(param, []string{"requires", "conflicts"}, paramIDs)
+	}
+	for _, preset := range presets {
+		rewriteRawReferences(preset, []string{"parameters", "remove_parameters"}, paramIDs)
+		if base, ok := preset["extends"].(string); ok {
+			if id, ok := presetIDs[base]; ok {
+				preset["extends"] = id
+			}
+		}
+	}
+	return nil
+}
+
+// rawEntries returns the entries of a raw config list
+func rawEntries(raw map[string]any, key string) ([]map[string]any, error) {
+	items, _ := raw[key].([]any)
+	entries := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected entry in %s", key)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// rawIDsByName maps entry names to their IDs
+func rawIDsByName(entries []map[string]any) map[string]string {
+	ids := make(map[string]string)
+	for _, entry := range entries {
+		name, _ := entry["name"].(string)
+		id, _ := entry["id"].(string)
+		if _, seen := ids[name]; !seen && id != "" {
+			ids[name] = id
+		}
+	}
+	return ids
+}
+
+// rewriteRawReferences replaces the names in the given list fields with IDs
+func rewriteRawReferences(entry map[string]any, fields []string, ids map[string]string) {
+	for _, field := range fields {
+		refs, _ := entry[field].([]any)
+		for i, ref := range refs {
+			if name, ok := ref.(string); ok {
+				if id, ok := ids[name]; ok {
+					refs[i] = id
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package setup
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported platform keys
+var Platforms = []string{"mobile", "desktop"}
+
+// platformLabels are the display names of the platforms
+var platformLabels = map[string]string{"mobile": "Mobile", "desktop": "Desktop"}
+
+// PlatformLabel returns the display name of a platform key, e.g. Desktop
+func PlatformLabel(platform string) string {
+	if label, ok := platformLabels[platform]; ok {
+		return label
+	}
+	return platform
+}
+
+// PlatformKey converts a platform base command to the platform key stored in presets,
+// mobile unless it starts Ledger Live Desktop
+func PlatformKey(baseCommand string) string {
+	if baseCommand == "pnpm dev:lld" {
+		return "desktop"
+	}
+	return "mobile"
+}
+
+// AppliesTo reports whether the parameter can be used on the given platform.
+// Parameters without platform restrictions apply everywhere.
+func (p Parameter) AppliesTo(platform string) bool {
+	if len(p.Platforms) == 0 || platform == "" {
+		return true
+	}
+	for _, allowed := range p.Platforms {
+		if allowed == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterParametersForPlatform returns only the parameters available on the given platform
+func FilterParametersForPlatform(params []Parameter, platform string) []Parameter {
+	var filtered []Parameter
+	for _, param := range params {
+		if param.AppliesTo(platform) {
+			filtered = append(filtered, param)
+		}
+	}
+	return filtered
+}
+
+// FindParameter looks up a parameter by name
+func FindParameter(name string, params []Parameter) *Parameter {
+	for i := range params {
+		if params[i].Name == name {
+			return &params[i]
+		}
+	}
+	return nil
+}
+
+// ResolveParameterSelection expands a selection of parameter IDs with all required
+// parameters and checks it against platform scoping and conflicts. It returns the
+// resolved selection (in selection order, requirements appended) and the IDs that
+// were added automatically.
+func ResolveParameterSelection(selected []string, params []Parameter, platform string) ([]string, []string, error) {
+	resolved := []string{}
+	var added []string
+	included := make(map[string]bool)
+
+	// Walk requirements breadth-first so chains (A requires B requires C) are followed
+	queue := append([]string{}, selected...)
+	explicit := len(selected)
+	for i := 0; i < len(queue); i++ {
+		id := queue[i]
+		if included[id] {
+			continue
+		}
+
+		param := ParameterByID(id, params)
+		if param == nil {
+			return nil, nil, fmt.Errorf("parameter '%s' does not exist", id)
+		}
+		if !param.AppliesTo(platform) {
+			return nil, nil, fmt.Errorf("'%s' is not available on %s (only %s)", param.Name, platform, strings.Join(param.Platforms, ", "))
+		}
+
+		included[id] = true
+		resolved = append(resolved, id)
+		if i >= explicit {
+			added = append(added, id)
+		}
+		queue = append(queue, param.Requires...)
+	}
+
+	// Conflicts are checked in both directions so only one side has to declare them
+	for _, id := range resolved {
+		param := ParameterByID(id, params)
+		for _, conflict := range param.Conflicts {
+			if included[conflict] {
+				return nil, nil, fmt.Errorf("'%s' conflicts with '%s'", param.Name, ParameterName(conflict, params))
+			}
+		}
+	}
+
+	return resolved, added, nil
+}
+
+// ValidatePreset checks that a preset satisfies the platform, requirement and
+// conflict rules of its parameters
+func ValidatePreset(preset Preset, params []Parameter) error {
+	_, added, err := ResolveParameterSelection(preset.Parameters, params, preset.Platform)
+	if err != nil {
+		return fmt.Errorf("preset '%s': %v", preset.Name, err)
+	}
+	if len(added) > 0 {
+		return fmt.Errorf("preset '%s': missing required parameter(s) %s", preset.Name, strings.Join(ParameterNames(added, params), ", "))
+	}
+	return nil
+}
+
+// ValidateParameterRules checks a parameter's platform, requirement and conflict declarations
+func ValidateParameterRules(param Parameter, params []Parameter) error {
+	for _, platform := range param.Platforms {
+		if !isKnownPlatform(platform) {
+			return fmt.Errorf("unknown platform '%s'", platform)
+		}
+	}
+
+	requires := make(map[string]bool)
+	for _, id := range param.Requires {
+		if id == param.ID {
+			return fmt.Errorf("parameter cannot require itself")
+		}
+		if ParameterByID(id, params) == nil {
+			return fmt.Errorf("required parameter '%s' does not exist", id)
+		}
+		requires[id] = true
+	}
+
+	for _, id := range param.Conflicts {
+		if id == param.ID {
+			return fmt.Errorf("parameter cannot conflict with itself")
+		}
+		if ParameterByID(id, params) == nil {
+			return fmt.Errorf("conflicting parameter '%s' does not exist", id)
+		}
+		if requires[id] {
+			return fmt.Errorf("'%s' cannot be both required and conflicting", ParameterName(id, params))
+		}
+	}
+	return nil
+}
+
+func isKnownPlatform(platform string) bool {
+	for _, known := range Platforms {
+		if known == platform {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package setup
+
+import "strings"
+
+// PresetGroups returns the names of all preset groups in order of first appearance
+func PresetGroups(presets []Preset) []string {
+	var groups []string
+	for _, preset := range presets {
+		group := strings.TrimSpace(preset.Group)
+		if group != "" {
+			groups = appendUnique(groups, group)
+		}
+	}
+	return groups
+}
+
+// PresetsInGroup returns the presets belonging to a group, or the ungrouped presets for ""
+func PresetsInGroup(group string, presets []Preset) []Preset {
+	var result []Preset
+	for _, preset := range presets {
+		if strings.TrimSpace(preset.Group) == group {
+			result = append(result, preset)
+		}
+	}
+	return result
+}
+
+// MovePresetsToGroup assigns the named presets to a group, "" removing them from any group
+func MovePresetsToGroup(names []string, group string, presets []Preset) {
+	group = strings.TrimSpace(group)
+	for _, name := range names {
+		if preset := FindPreset(name, presets); preset != nil {
+			preset.Group = group
+		}
+	}
+}
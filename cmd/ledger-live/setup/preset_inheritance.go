@@ -0,0 +1,174 @@
+package setup
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindPreset looks up a preset by name
+func FindPreset(name string, presets []Preset) *Preset {
+	for i := range presets {
+		if presets[i].Name == name {
+			return &presets[i]
+		}
+	}
+	return nil
+}
+
+// ResolvePreset returns the effective preset after applying its `extends` chain.
+// The child inherits the platform and parameters of its base, overrides the platform
+// if it sets one, drops the inherited parameters listed in RemoveParameters and adds
+// its own parameters. Env overrides are merged with the child winning, and the working
+// directory and arguments are inherited unless the child sets its own. The returned
+// preset no longer extends anything.
+func ResolvePreset(preset Preset, presets []Preset) (Preset, error) {
+	return resolvePresetChain(preset, presets, nil)
+}
+
+func resolvePresetChain(preset Preset, presets []Preset, chain []Preset) (Preset, error) {
+	for _, p := range chain {
+		if p.ID == preset.ID {
+			var cycle []string
+			for _, c := range append(chain, preset) {
+				cycle = append(cycle, c.Name)
+			}
+			return Preset{}, fmt.Errorf("preset inheritance cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	chain = append(chain, preset)
+
+	if preset.Extends == "" {
+		resolved := preset
+		resolved.Parameters = append([]string{}, preset.Parameters...)
+		resolved.RemoveParameters = nil
+		return resolved, nil
+	}
+
+	base := PresetByID(preset.Extends, presets)
+	if base == nil {
+		return Preset{}, fmt.Errorf("preset '%s' extends unknown preset '%s'", preset.Name, preset.Extends)
+	}
+	resolvedBase, err := resolvePresetChain(*base, presets, chain)
+	if err != nil {
+		return Preset{}, err
+	}
+
+	resolved := preset
+	resolved.Extends = ""
+	resolved.RemoveParameters = nil
+	if resolved.Platform == "" {
+		resolved.Platform = resolvedBase.Platform
+	}
+	if resolved.WorkDir == "" {
+		resolved.WorkDir = resolvedBase.WorkDir
+	}
+	if len(resolved.Args) == 0 {
+		resolved.Args = resolvedBase.Args
+	}
+	if len(resolvedBase.Env) > 0 {
+		resolved.Env = make(map[string]string)
+		for key, value := range resolvedBase.Env {
+			resolved.Env[key] = value
+		}
+		for key, value := range preset.Env {
+			resolved.Env[key] = value
+		}
+	}
+
+	// Inherited parameters first, minus removals, then local additions
+	removed := make(map[string]bool)
+	for _, id := range preset.RemoveParameters {
+		removed[id] = true
+	}
+	resolved.Parameters = nil
+	for _, id := range resolvedBase.Parameters {
+		if !removed[id] {
+			resolved.Parameters = appendUnique(resolved.Parameters, id)
+		}
+	}
+	for _, id := range preset.Parameters {
+		resolved.Parameters = appendUnique(resolved.Parameters, id)
+	}
+
+	return resolved, nil
+}
+
+// InheritedPreset returns the effective settings a preset gets from its base,
+// or nil if the preset does not extend another one
+func InheritedPreset(preset Preset, presets []Preset) (*Preset, error) {
+	if preset.Extends == "" {
+		return nil, nil
+	}
+	base := PresetByID(preset.Extends, presets)
+	if base == nil {
+		return nil, fmt.Errorf("preset '%s' extends unknown preset '%s'", preset.Name, preset.Extends)
+	}
+	resolvedBase, err := resolvePresetChain(*base, presets, []Preset{preset})
+	if err != nil {
+		return nil, err
+	}
+	return &resolvedBase, nil
+}
+
+// ValidateExtends checks that a preset's base exists and does not create a cycle
+func ValidateExtends(preset Preset, presets []Preset) error {
+	if preset.Extends == "" {
+		return nil
+	}
+	if preset.Extends == preset.ID {
+		return fmt.Errorf("preset cannot extend itself")
+	}
+
+	// Check against the list as it would look with this preset saved
+	candidates := make([]Preset, 0, len(presets)+1)
+	replaced := false
+	for _, p := range presets {
+		if p.ID == preset.ID {
+			candidates = append(candidates, preset)
+			replaced = true
+		} else {
+			candidates = append(candidates, p)
+		}
+	}
+	if !replaced {
+		candidates = append(candidates, preset)
+	}
+
+	_, err := ResolvePreset(preset, candidates)
+	return err
+}
+
+// PresetChildren returns the names of presets that directly extend the preset with the given ID
+func PresetChildren(id string, presets []Preset) []string {
+	var children []string
+	for _, preset := range presets {
+		if preset.Extends == id {
+			children = append(children, preset.Name)
+		}
+	}
+	return children
+}
+
+// DetachPreset replaces a preset's inheritance with the settings it currently
+// resolves to, so it keeps working after its base is deleted
+func DetachPreset(name string, presets []Preset) error {
+	preset := FindPreset(name, presets)
+	if preset == nil || preset.Extends == "" {
+		return nil
+	}
+	resolved, err := ResolvePreset(*preset, presets)
+	if err != nil {
+		return err
+	}
+	*preset = resolved
+	return nil
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
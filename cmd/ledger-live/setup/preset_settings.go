@@ -0,0 +1,84 @@
+package setup
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ParseEnvOverrides parses one KEY=value pair per line, ignoring blank lines and # comments
+func ParseEnvOverrides(text string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expected KEY=value, got %q", i+1, line)
+		}
+		overrides[key] = value
+	}
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+	return overrides, nil
+}
+
+// FormatEnvOverrides formats env overrides as sorted KEY=value lines
+func FormatEnvOverrides(overrides map[string]string) string {
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, key+"="+overrides[key])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseArgs reads extra command arguments, one per line so arguments may contain spaces
+func ParseArgs(text string) []string {
+	var args []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			args = append(args, line)
+		}
+	}
+	return args
+}
+
+// FormatArgs formats extra command arguments one per line
+func FormatArgs(args []string) string {
+	return strings.Join(args, "\n")
+}
+
+// ValidateWorkDir checks that a preset working directory stays inside the Ledger Live path
+func ValidateWorkDir(workDir string) error {
+	workDir = strings.TrimSpace(workDir)
+	if workDir == "" {
+		return nil
+	}
+	if filepath.IsAbs(workDir) {
+		return fmt.Errorf("working directory must be relative to the Ledger Live path")
+	}
+	cleaned := filepath.Clean(workDir)
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("working directory must stay inside the Ledger Live path")
+	}
+	return nil
+}
+
+// PresetWorkingDir returns the directory a preset runs in
+func PresetWorkingDir(preset Preset, ledgerLivePath string) string {
+	if strings.TrimSpace(preset.WorkDir) == "" {
+		return ledgerLivePath
+	}
+	return filepath.Join(ledgerLivePath, filepath.Clean(preset.WorkDir))
+}
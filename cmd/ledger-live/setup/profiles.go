@@ -0,0 +1,221 @@
+package setup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile uses the config directly in ~/.ledger-live, as before profiles existed
+const DefaultProfile = "default"
+
+// Profiles live in their own directory so each one keeps its own vault, usage and backups
+const profilesDirName = "profiles"
+
+// File in ~/.ledger-live naming the profile selected with `profile use`
+const activeProfileFileName = "active-profile"
+
+// Environment variable selecting a profile for a single run
+const ProfileEnv = "LEDGER_LIVE_STARTER_PROFILE"
+
+// Files copied by CopyProfile, besides the config file
+var profileDataFiles = []string{vaultFileName, usageFileName}
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Profile set with the --profile flag
+var profileFlag string
+
+func SetProfile(name string) {
+	profileFlag = name
+}
+
+// ActiveProfile returns the profile in use, from the --profile flag, the environment or
+// `profile use`. It is empty when --config or LEDGER_LIVE_STARTER_CONFIG picks the file.
+func ActiveProfile() string {
+	if ConfigPathIsExplicit() {
+		return ""
+	}
+	if profileFlag != "" {
+		return profileFlag
+	}
+	if name := os.Getenv(ProfileEnv); name != "" && ProfileExists(name) {
+		return name
+	}
+	return selectedProfile()
+}
+
+// selectedProfile reads the profile chosen with `profile use`
+func selectedProfile() string {
+	data, err := os.ReadFile(filepath.Join(GetLedgerLiveDir(), activeProfileFileName))
+	if err != nil {
+		return DefaultProfile
+	}
+	name := strings.TrimSpace(string(data))
+	if name == "" || !ProfileExists(name) {
+		return DefaultProfile
+	}
+	return name
+}
+
+// ValidateProfileName checks that a profile name is usable as a directory name
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("profile name '%s' may only contain letters, digits, '.', '-' and '_'", name)
+	}
+	return nil
+}
+
+// GetProfileDir returns the directory holding a profile's files
+func GetProfileDir(name string) string {
+	if name == DefaultProfile {
+		return GetLedgerLiveDir()
+	}
+	return filepath.Join(GetLedgerLiveDir(), profilesDirName, name)
+}
+
+// GetProfileConfigPath returns the config file of a profile, in whichever format it uses
+func GetProfileConfigPath(name string) string {
+	return configFileInDir(GetProfileDir(name))
+}
+
+// ProfileExists checks if a profile has a config file
+func ProfileExists(name string) bool {
+	if ValidateProfileName(name) != nil {
+		return false
+	}
+	_, err := os.Stat(GetProfileConfigPath(name))
+	return err == nil
+}
+
+// ListProfiles returns the names of all profiles with a config, the default one first
+func ListProfiles() []string {
+	profiles := []string{DefaultProfile}
+
+	entries, err := os.ReadDir(filepath.Join(GetLedgerLiveDir(), profilesDirName))
+	if err != nil {
+		return profiles
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != DefaultProfile && ProfileExists(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return append(profiles, names...)
+}
+
+// CreateProfile creates a profile with the default parameters and no presets.
+// The Ledger Live path is taken over from the active profile when it has one.
+func CreateProfile(name string) error {
+	if err := checkNewProfile(name); err != nil {
+		return err
+	}
+
+	config := GetDefaultConfig()
+	currentPath := GetProfileConfigPath(ActiveProfileOrDefault())
+	if current, err := os.ReadFile(currentPath); err == nil {
+		if existing, err := decodeConfig(current, FormatForPath(currentPath)); err == nil {
+			config.LedgerLivePath = existing.LedgerLivePath
+		}
+	}
+	return SaveConfigToPath(config, filepath.Join(GetProfileDir(name), configFileNames[0]))
+}
+
+// CopyProfile copies a profile's config, secrets vault and usage into a new profile
+func CopyProfile(source string, target string) error {
+	if !ProfileExists(source) {
+		return fmt.Errorf("profile '%s' does not exist", source)
+	}
+	if err := checkNewProfile(target); err != nil {
+		return err
+	}
+
+	sourceDir := GetProfileDir(source)
+	targetDir := GetProfileDir(target)
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return err
+	}
+
+	configFile := filepath.Base(GetProfileConfigPath(source))
+	for _, name := range append([]string{configFile}, profileDataFiles...) {
+		if err := copyProfileFile(filepath.Join(sourceDir, name), filepath.Join(targetDir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseProfile makes a profile the one used when no --profile flag is given
+func UseProfile(name string) error {
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile '%s' does not exist, create it with 'ledger-live profile create %s'", name, name)
+	}
+	if err := EnsureConfigDirExists(); err != nil {
+		return err
+	}
+	return WriteFileAtomic(filepath.Join(GetLedgerLiveDir(), activeProfileFileName), []byte(name+"\n"), 0644)
+}
+
+// DeleteProfile removes a profile and all its files. Deleting the selected profile
+// switches back to the default one.
+func DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile cannot be deleted")
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile '%s' does not exist", name)
+	}
+
+	if selectedProfile() == name {
+		if err := os.Remove(filepath.Join(GetLedgerLiveDir(), activeProfileFileName)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.RemoveAll(GetProfileDir(name))
+}
+
+// ActiveProfileOrDefault returns the active profile, or the default one when a config file was given directly
+func ActiveProfileOrDefault() string {
+	if profile := ActiveProfile(); profile != "" {
+		return profile
+	}
+	return DefaultProfile
+}
+
+func checkNewProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if name == DefaultProfile || ProfileExists(name) {
+		return fmt.Errorf("profile '%s' already exists", name)
+	}
+	return nil
+}
+
+// copyProfileFile copies a file, ignoring sources that do not exist
+func copyProfileFile(source string, target string) error {
+	in, err := os.Open(source)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(target, data, info.Mode().Perm())
+}
@@ -0,0 +1,292 @@
+package setup
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Presets refer to parameters and to their base preset, and parameters to the parameters
+// they require or conflict with, by ID. Names are only looked up for display, so renaming
+// an entry, by hand or in another instance, never breaks what refers to it.
+
+// NewID returns a random identifier for a parameter or preset
+func NewID() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("failed to generate id: %v", err))
+	}
+	return hex.EncodeToString(buf)
+}
+
+// nameID derives an ID from an entry's kind and name, so every instance reading a
+// hand-written entry without an ID assigns it the same one
+func nameID(kind string, name string) string {
+	sum := sha256.Sum256([]byte(kind + "\x00" + name))
+	return hex.EncodeToString(sum[:6])
+}
+
+// assignID returns the ID derived from the name, or a random one if it is already taken
+func assignID(kind string, name string, taken map[string]bool) string {
+	id := nameID(kind, name)
+	for taken[id] {
+		id = NewID()
+	}
+	taken[id] = true
+	return id
+}
+
+// EnsureIDs assigns IDs to parameters and presets that have none, reporting whether any changed
+func EnsureIDs(config *Config) bool {
+	changed := false
+	paramIDs := make(map[string]bool)
+	for _, param := range config.Parameters {
+		paramIDs[param.ID] = true
+	}
+	for i := range config.Parameters {
+		if config.Parameters[i].ID == "" {
+			config.Parameters[i].ID = assignID("parameter", config.Parameters[i].Name, paramIDs)
+			changed = true
+		}
+	}
+	presetIDs := make(map[string]bool)
+	for _, preset := range config.Presets {
+		presetIDs[preset.ID] = true
+	}
+	for i := range config.Presets {
+		if config.Presets[i].ID == "" {
+			config.Presets[i].ID = assignID("preset", config.Presets[i].Name, presetIDs)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// FindParameterByID returns the index of the parameter with the given ID, or -1
+func FindParameterByID(id string, params []Parameter) int {
+	for i := range params {
+		if params[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindPresetByID returns the index of the preset with the given ID, or -1
+func FindPresetByID(id string, presets []Preset) int {
+	for i := range presets {
+		if presets[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParameterByID returns the parameter with the given ID, or nil
+func ParameterByID(id string, params []Parameter) *Parameter {
+	if i := FindParameterByID(id, params); i >= 0 && id != "" {
+		return &params[i]
+	}
+	return nil
+}
+
+// PresetByID returns the preset with the given ID, or nil
+func PresetByID(id string, presets []Preset) *Preset {
+	if i := FindPresetByID(id, presets); i >= 0 && id != "" {
+		return &presets[i]
+	}
+	return nil
+}
+
+// ParameterName returns the name of a referenced parameter, or the ID if it does not exist
+func ParameterName(id string, params []Parameter) string {
+	if param := ParameterByID(id, params); param != nil {
+		return param.Name
+	}
+	return id
+}
+
+// ParameterNames returns the names of referenced parameters, for display
+func ParameterNames(ids []string, params []Parameter) []string {
+	var names []string
+	for _, id := range ids {
+		names = append(names, ParameterName(id, params))
+	}
+	return names
+}
+
+// PresetName returns the name of a referenced preset, or the ID if it does not exist
+func PresetName(id string, presets []Preset) string {
+	if preset := PresetByID(id, presets); preset != nil {
+		return preset.Name
+	}
+	return id
+}
+
+// ParameterIDs looks up parameters by name, e.g. for names given on the command line
+func ParameterIDs(names []string, params []Parameter) ([]string, error) {
+	var ids []string
+	for _, name := range names {
+		param := FindParameter(name, params)
+		if param == nil {
+			return nil, fmt.Errorf("parameter '%s' does not exist", name)
+		}
+		ids = appendUnique(ids, param.ID)
+	}
+	return ids, nil
+}
+
+// ResolveReferenceNames assigns missing IDs and converts references written as names,
+// e.g. by hand or by versions before IDs, to the IDs of the entries they name.
+// References that already are IDs, or match nothing, are kept as they are.
+func ResolveReferenceNames(config *Config) {
+	EnsureIDs(config)
+	resolveReferenceNames(config, config)
+}
+
+// resolveReferenceNames converts the name references in config using the entries of lookup
+func resolveReferenceNames(config *Config, lookup *Config) {
+	paramID := func(ref string) string {
+		if ParameterByID(ref, lookup.Parameters) == nil {
+			if param := FindParameter(ref, lookup.Parameters); param != nil && param.ID != "" {
+				return param.ID
+			}
+		}
+		return ref
+	}
+	paramIDs := func(refs []string) []string {
+		if refs == nil {
+			return nil
+		}
+		result := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			result = appendUnique(result, paramID(ref))
+		}
+		return result
+	}
+
+	for i := range config.Parameters {
+		config.Parameters[i].Requires = paramIDs(config.Parameters[i].Requires)
+		config.Parameters[i].Conflicts = paramIDs(config.Parameters[i].Conflicts)
+	}
+	for i := range config.Presets {
+		preset := &config.Presets[i]
+		preset.Parameters = paramIDs(preset.Parameters)
+		preset.RemoveParameters = paramIDs(preset.RemoveParameters)
+		if preset.Extends != "" && PresetByID(preset.Extends, lookup.Presets) == nil {
+			if base := FindPreset(preset.Extends, lookup.Presets); base != nil && base.ID != "" {
+				preset.Extends = base.ID
+			}
+		}
+	}
+}
+
+// ParameterReferences lists what refers to a set of parameters
+type ParameterReferences struct {
+	Presets    []string // Names of the presets using or removing the parameters
+	Parameters []string // Names of other parameters requiring or conflicting with them
+}
+
+// Empty reports whether nothing refers to the parameters
+func (r ParameterReferences) Empty() bool {
+	return len(r.Presets) == 0 && len(r.Parameters) == 0
+}
+
+// FindParameterReferences returns the presets and other parameters referring to the parameters with the given IDs
+func FindParameterReferences(ids []string, config *Config) ParameterReferences {
+	var refs ParameterReferences
+	for _, preset := range config.Presets {
+		if containsAny(preset.Parameters, ids) || containsAny(preset.RemoveParameters, ids) {
+			refs.Presets = append(refs.Presets, preset.Name)
+		}
+	}
+	for _, param := range config.Parameters {
+		if containsAny([]string{param.ID}, ids) {
+			continue
+		}
+		if containsAny(param.Requires, ids) || containsAny(param.Conflicts, ids) {
+			refs.Parameters = append(refs.Parameters, param.Name)
+		}
+	}
+	return refs
+}
+
+// ReplaceParameterReferences points references to any of the IDs at the replacement ID,
+// or drops them if replacement is empty
+func ReplaceParameterReferences(ids []string, replacement string, config *Config) {
+	for i := range config.Presets {
+		config.Presets[i].Parameters = replaceIDs(config.Presets[i].Parameters, ids, replacement)
+		config.Presets[i].RemoveParameters = replaceIDs(config.Presets[i].RemoveParameters, ids, replacement)
+	}
+	for i := range config.Parameters {
+		// A parameter cannot require or conflict with itself
+		self := config.Parameters[i].ID
+		config.Parameters[i].Requires = withoutID(replaceIDs(config.Parameters[i].Requires, ids, replacement), self)
+		config.Parameters[i].Conflicts = withoutID(replaceIDs(config.Parameters[i].Conflicts, ids, replacement), self)
+	}
+}
+
+// RemoveParameterReferences drops every reference to the parameters with the given IDs
+func RemoveParameterReferences(ids []string, config *Config) {
+	ReplaceParameterReferences(ids, "", config)
+}
+
+// DanglingParameterReferences describes preset and rule references to parameters that do not exist
+func DanglingParameterReferences(config *Config) []string {
+	var problems []string
+	for _, preset := range config.Presets {
+		for _, id := range preset.Parameters {
+			if ParameterByID(id, config.Parameters) == nil {
+				problems = append(problems, fmt.Sprintf("preset '%s' references unknown parameter '%s'", preset.Name, id))
+			}
+		}
+	}
+	for _, param := range config.Parameters {
+		for _, id := range append(append([]string{}, param.Requires...), param.Conflicts...) {
+			if ParameterByID(id, config.Parameters) == nil {
+				problems = append(problems, fmt.Sprintf("parameter '%s' references unknown parameter '%s'", param.Name, id))
+			}
+		}
+	}
+	return problems
+}
+
+// replaceIDs replaces IDs in a list, dropping them if replacement is empty, without duplicates
+func replaceIDs(list []string, ids []string, replacement string) []string {
+	if list == nil {
+		return nil
+	}
+	result := make([]string, 0, len(list))
+	for _, id := range list {
+		if containsAny([]string{id}, ids) {
+			if replacement == "" {
+				continue
+			}
+			id = replacement
+		}
+		result = appendUnique(result, id)
+	}
+	return result
+}
+
+func withoutID(list []string, id string) []string {
+	var result []string
+	for _, i := range list {
+		if i != id {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func containsAny(list []string, names []string) bool {
+	for _, a := range list {
+		for _, b := range names {
+			if a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
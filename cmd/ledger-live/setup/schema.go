@@ -0,0 +1,95 @@
+package setup
+
+import "encoding/json"
+
+// ConfigSchema returns a JSON Schema describing the config file, for editor autocompletion
+func ConfigSchema() ([]byte, error) {
+	stringList := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	platform := map[string]any{"type": "string", "enum": Platforms}
+
+	parameter := map[string]any{
+		"type":     "object",
+		"required": []string{"name", "env_var"},
+		"properties": map[string]any{
+			"id":          map[string]any{"type": "string", "description": "Stable identifier, assigned automatically"},
+			"name":        map[string]any{"type": "string", "minLength": 1, "description": "Unique parameter name"},
+			"env_var":     map[string]any{"type": "string", "description": "NAME=value, or only NAME for secret parameters"},
+			"description": map[string]any{"type": "string"},
+			"platforms":   map[string]any{"type": "array", "items": platform, "description": "Platforms the parameter applies to, empty for all"},
+			"requires":    withDescription(stringList, "IDs (or names) of parameters that must be selected too"),
+			"conflicts":   withDescription(stringList, "IDs (or names) of parameters that cannot be selected together"),
+			"category":    map[string]any{"type": "string"},
+			"tags":        stringList,
+			"secret":      map[string]any{"type": "boolean", "description": "Value is kept in the encrypted vault"},
+		},
+		"additionalProperties": false,
+	}
+
+	preset := map[string]any{
+		"type":     "object",
+		"required": []string{"name"},
+		"properties": map[string]any{
+			"id":                map[string]any{"type": "string", "description": "Stable identifier, assigned automatically"},
+			"name":              map[string]any{"type": "string", "minLength": 1, "description": "Unique preset name"},
+			"extends":           map[string]any{"type": "string", "description": "ID (or name) of the base preset"},
+			"platform":          map[string]any{"type": "string", "enum": append([]string{""}, Platforms...)},
+			"parameters":        withDescription(stringList, "IDs (or names) of the parameters to enable"),
+			"remove_parameters": withDescription(stringList, "IDs (or names) of inherited parameters to drop"),
+			"favorite":          map[string]any{"type": "boolean"},
+			"group":             map[string]any{"type": "string"},
+			"env":               map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"note":              map[string]any{"type": "string"},
+			"work_dir":          map[string]any{"type": "string", "description": "Subdirectory of ledger-live-path"},
+			"args":              stringList,
+		},
+		"additionalProperties": false,
+	}
+
+	hexColor := map[string]any{"type": "string", "pattern": hexColorPattern.String()}
+	colorProperties := map[string]any{}
+	for _, role := range ThemeColorRoles {
+		colorProperties[role] = map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{"light": hexColor, "dark": hexColor},
+			"additionalProperties": false,
+		}
+	}
+	theme := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":   map[string]any{"type": "string", "enum": ThemeNames, "description": "Built-in theme to start from (default \"default\")"},
+			"colors": map[string]any{"type": "object", "properties": colorProperties, "additionalProperties": false, "description": "Colors overriding the built-in theme, for light and dark terminals"},
+		},
+		"additionalProperties": false,
+	}
+
+	schema := map[string]any{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"title":    "Ledger Live Starter configuration",
+		"type":     "object",
+		"required": []string{"ledger-live-path", "parameters"},
+		"properties": map[string]any{
+			"$schema":          map[string]any{"type": "string", "description": "Path or URL of this schema, for editors"},
+			"schema_version":   map[string]any{"type": "integer", "minimum": 1, "maximum": CurrentSchemaVersion},
+			"ledger-live-path": map[string]any{"type": "string", "description": "Path to the Ledger Live repository"},
+			"parameters":       map[string]any{"type": "array", "items": parameter},
+			"presets":          map[string]any{"type": "array", "items": preset},
+			"update_check":     map[string]any{"type": "boolean", "description": "Check for new starter versions on start (default true)"},
+			"update_endpoint":  map[string]any{"type": "string", "description": "Releases API of the starter repository, e.g. a local test server"},
+			"update_channel":   map[string]any{"type": "string", "enum": []string{ChannelStable, ChannelBeta}, "description": "Release channel of the starter: stable, or beta to include pre-releases (default stable)"},
+			"theme":            theme,
+		},
+		"additionalProperties": false,
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func withDescription(schema map[string]any, description string) map[string]any {
+	result := make(map[string]any, len(schema)+1)
+	for key, value := range schema {
+		result[key] = value
+	}
+	result["description"] = description
+	return result
+}
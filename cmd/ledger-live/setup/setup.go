@@ -2,6 +2,8 @@ package setup
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -63,7 +65,7 @@ func RunSetupMode() (*Config, error) {
 	fmt.Printf("\n%s %s\n", TitleText("Parameters:"), NormalText("Default parameters available:"))
 	for i, param := range config.Parameters {
 		fmt.Printf("  %d. %s - %s\n", i+1, HighlightText(param.Name), NormalText(param.Description))
-		fmt.Printf("     %s\n", NormalText(param.EnvVar))
+		fmt.Printf("     %s\n", NormalText(MaskedEnvVar(param)))
 	}
 	fmt.Println()
 
@@ -169,6 +171,7 @@ func addCustomParameters(config *Config) *Config {
 
 		// Add parameter
 		newParam := Parameter{
+			ID:          NewID(),
 			Name:        strings.TrimSpace(name),
 			EnvVar:      strings.TrimSpace(envVar),
 			Description: strings.TrimSpace(description),
@@ -198,6 +201,10 @@ func addCustomParameters(config *Config) *Config {
 // We'll define them as variables that get set from main
 var (
 	RunStyledForm func(*huh.Form) error
+	CanPrompt     func() bool // Whether RunStyledForm can ask the user, false without a terminal
+
+	// Info and warning messages go here, stderr while a command prints a machine-readable document
+	Diagnostics io.Writer = os.Stdout
 	
 	// Theme-based text functions
 	TitleText     func(text string) string
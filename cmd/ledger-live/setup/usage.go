@@ -0,0 +1,115 @@
+package setup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Usage file name, stored next to the config file
+const usageFileName = "usage.json"
+
+// PresetUsage tracks how often and how recently a preset was started
+type PresetUsage struct {
+	Name     string    `json:"name,omitempty"` // Name when last started, for display once the preset is gone
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// UsageStats is the locally tracked preset usage, kept out of the shareable config.
+// Presets are keyed by ID; files written before IDs were used key them by name.
+type UsageStats struct {
+	LastPreset string                 `json:"last_preset,omitempty"`
+	Presets    map[string]PresetUsage `json:"presets"`
+}
+
+// GetUsagePath returns the path of the usage statistics file
+func GetUsagePath() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), usageFileName)
+}
+
+// LoadUsage reads the usage statistics, returning empty stats if none exist yet
+func LoadUsage() *UsageStats {
+	usage := &UsageStats{Presets: make(map[string]PresetUsage)}
+
+	data, err := os.ReadFile(GetUsagePath())
+	if err != nil {
+		return usage
+	}
+	if err := json.Unmarshal(data, usage); err != nil || usage.Presets == nil {
+		return &UsageStats{Presets: make(map[string]PresetUsage)}
+	}
+	return usage
+}
+
+// SaveUsage writes the usage statistics
+func SaveUsage(usage *UsageStats) error {
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(GetUsagePath()), 0755); err != nil {
+		return err
+	}
+	return WriteFileAtomic(GetUsagePath(), data, 0644)
+}
+
+// RecordPresetRun counts a preset start and remembers it as the last run preset
+func RecordPresetRun(preset Preset) error {
+	return WithFileLock(GetUsagePath(), func() error {
+		usage := LoadUsage()
+		stats := usage.For(preset)
+		delete(usage.Presets, preset.Name)
+		stats.Name = preset.Name
+		stats.Count++
+		stats.LastUsed = time.Now()
+		usage.Presets[preset.ID] = stats
+		usage.LastPreset = preset.ID
+		return SaveUsage(usage)
+	})
+}
+
+// For returns the usage of a preset, including usage recorded under its name
+func (u *UsageStats) For(preset Preset) PresetUsage {
+	if stats, ok := u.Presets[preset.ID]; ok {
+		return stats
+	}
+	return u.Presets[preset.Name]
+}
+
+// LastPresetIn returns the last run preset if it is still in the list, or nil
+func (u *UsageStats) LastPresetIn(presets []Preset) *Preset {
+	if u.LastPreset == "" {
+		return nil
+	}
+	if preset := PresetByID(u.LastPreset, presets); preset != nil {
+		return preset
+	}
+	return FindPreset(u.LastPreset, presets)
+}
+
+// SortPresetsForMenu orders presets for the start menu: favorites first in file order,
+// then the rest by most recent use, then by how often they were used, then file order
+func SortPresetsForMenu(presets []Preset, usage *UsageStats) []Preset {
+	var favorites, others []Preset
+	for _, preset := range presets {
+		if preset.Favorite {
+			favorites = append(favorites, preset)
+		} else {
+			others = append(others, preset)
+		}
+	}
+
+	sort.SliceStable(others, func(i, j int) bool {
+		a := usage.For(others[i])
+		b := usage.For(others[j])
+		if !a.LastUsed.Equal(b.LastUsed) {
+			return a.LastUsed.After(b.LastUsed)
+		}
+		return a.Count > b.Count
+	})
+
+	return append(favorites, others...)
+}
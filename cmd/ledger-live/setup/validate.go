@@ -0,0 +1,321 @@
+package setup
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Severity of a validation finding
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationIssue is a single finding, located by a JSON path such as $.presets[1].parameters[0]
+type ValidationIssue struct {
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+}
+
+// ValidationReport collects the findings for a config file
+type ValidationReport struct {
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// HasErrors reports whether the report contains errors
+func (r *ValidationReport) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// Count returns the number of findings with a severity
+func (r *ValidationReport) Count(severity Severity) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *ValidationReport) errorf(path string, format string, args ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{Severity: SeverityError, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *ValidationReport) warnf(path string, format string, args ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{Severity: SeverityWarning, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateConfigFile checks a config file without modifying it. Older schema versions are
+// migrated in memory first, so the report reflects what the tool would load.
+func ValidateConfigFile(path string) (*ValidationReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+
+	report := &ValidationReport{}
+	migrated, _, _, err := migrateConfigData(data, path)
+	if err != nil {
+		report.errorf("$", "%v", err)
+		return report, nil
+	}
+
+	config, err := decodeMigratedConfig(migrated)
+	if err != nil {
+		report.errorf("$", "invalid config: %v", err)
+		return report, nil
+	}
+
+	validateConfig(config, report)
+	return report, nil
+}
+
+// ValidateConfig checks a loaded config for errors and hygiene issues
+func ValidateConfig(config *Config) *ValidationReport {
+	report := &ValidationReport{}
+	validateConfig(config, report)
+	return report
+}
+
+func validateConfig(config *Config, report *ValidationReport) {
+	if strings.TrimSpace(config.LedgerLivePath) == "" {
+		report.errorf("$.ledger-live-path", "Ledger Live path is not set")
+	} else if info, err := os.Stat(config.LedgerLivePath); err != nil || !info.IsDir() {
+		report.warnf("$.ledger-live-path", "directory %s does not exist", config.LedgerLivePath)
+	}
+	if config.UpdateEndpoint != "" {
+		if u, err := url.Parse(config.UpdateEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			report.errorf("$.update_endpoint", "must be an http or https URL")
+		}
+	}
+	if config.UpdateChannel != "" && config.UpdateChannel != ChannelStable && config.UpdateChannel != ChannelBeta {
+		report.errorf("$.update_channel", "unknown channel '%s', must be %s or %s", config.UpdateChannel, ChannelStable, ChannelBeta)
+	}
+	if config.Theme != nil {
+		validateTheme(config.Theme, report)
+	}
+
+	validateParameters(config, report)
+	validatePresets(config, report)
+	reportHygiene(config, report)
+}
+
+// hexColorPattern matches #rgb and #rrggbb colors
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func validateTheme(theme *Theme, report *ValidationReport) {
+	if theme.Name != "" && !containsAny(ThemeNames, []string{theme.Name}) {
+		report.errorf("$.theme.name", "unknown theme '%s' (expected one of: %s)", theme.Name, strings.Join(ThemeNames, ", "))
+	}
+	for role, color := range theme.Colors {
+		path := "$.theme.colors." + role
+		if !containsAny(ThemeColorRoles, []string{role}) {
+			report.errorf(path, "unknown color role '%s' (expected one of: %s)", role, strings.Join(ThemeColorRoles, ", "))
+			continue
+		}
+		if color.Light == "" && color.Dark == "" {
+			report.warnf(path, "sets neither a light nor a dark color")
+		}
+		for _, value := range []string{color.Light, color.Dark} {
+			if value != "" && !hexColorPattern.MatchString(value) {
+				report.errorf(path, "'%s' is not a hex color like #7200c9", value)
+			}
+		}
+	}
+}
+
+func validateParameters(config *Config, report *ValidationReport) {
+	names := make(map[string]int)
+	ids := make(map[string]int)
+
+	for i, param := range config.Parameters {
+		path := fmt.Sprintf("$.parameters[%d]", i)
+
+		if strings.TrimSpace(param.Name) == "" {
+			report.errorf(path+".name", "parameter name is empty")
+		} else if first, ok := names[param.Name]; ok {
+			report.errorf(path+".name", "duplicate parameter name '%s' (first used at $.parameters[%d])", param.Name, first)
+		} else {
+			names[param.Name] = i
+		}
+
+		if param.ID != "" {
+			if first, ok := ids[param.ID]; ok {
+				report.errorf(path+".id", "duplicate id '%s' (first used at $.parameters[%d])", param.ID, first)
+			} else {
+				ids[param.ID] = i
+			}
+		}
+
+		if message := envVarProblem(param); message != "" {
+			report.errorf(path+".env_var", "%s", message)
+		}
+
+		for j, platform := range param.Platforms {
+			if !isKnownPlatform(platform) {
+				report.errorf(fmt.Sprintf("%s.platforms[%d]", path, j), "unknown platform '%s' (expected one of: %s)", platform, strings.Join(Platforms, ", "))
+			}
+		}
+		for j, id := range param.Requires {
+			if ParameterByID(id, config.Parameters) == nil {
+				report.errorf(fmt.Sprintf("%s.requires[%d]", path, j), "unknown parameter '%s'", id)
+			}
+		}
+		for j, id := range param.Conflicts {
+			if ParameterByID(id, config.Parameters) == nil {
+				report.errorf(fmt.Sprintf("%s.conflicts[%d]", path, j), "unknown parameter '%s'", id)
+			} else if containsAny(param.Requires, []string{id}) {
+				report.errorf(fmt.Sprintf("%s.conflicts[%d]", path, j), "'%s' is both required and conflicting", ParameterName(id, config.Parameters))
+			}
+		}
+	}
+}
+
+// envVarProblem describes what is wrong with a parameter's env_var, or returns ""
+func envVarProblem(param Parameter) string {
+	value := strings.TrimSpace(param.EnvVar)
+	if value == "" {
+		return "environment variable is empty"
+	}
+	if param.Secret {
+		if strings.ContainsAny(value, "= \t") {
+			return "secret parameters hold only the variable name (e.g. API_TOKEN)"
+		}
+		return ""
+	}
+	key, _, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Sprintf("'%s' must have the form NAME=value", param.EnvVar)
+	}
+	if strings.TrimSpace(key) == "" || strings.ContainsAny(key, " \t") {
+		return fmt.Sprintf("'%s' has an invalid variable name", param.EnvVar)
+	}
+	return ""
+}
+
+func validatePresets(config *Config, report *ValidationReport) {
+	names := make(map[string]int)
+	ids := make(map[string]int)
+
+	for i, preset := range config.Presets {
+		path := fmt.Sprintf("$.presets[%d]", i)
+
+		if strings.TrimSpace(preset.Name) == "" {
+			report.errorf(path+".name", "preset name is empty")
+		} else if first, ok := names[preset.Name]; ok {
+			report.errorf(path+".name", "duplicate preset name '%s' (first used at $.presets[%d])", preset.Name, first)
+		} else {
+			names[preset.Name] = i
+		}
+
+		if preset.ID != "" {
+			if first, ok := ids[preset.ID]; ok {
+				report.errorf(path+".id", "duplicate id '%s' (first used at $.presets[%d])", preset.ID, first)
+			} else {
+				ids[preset.ID] = i
+			}
+		}
+
+		if preset.Platform != "" && !isKnownPlatform(preset.Platform) {
+			report.errorf(path+".platform", "unknown platform '%s' (expected one of: %s)", preset.Platform, strings.Join(Platforms, ", "))
+		}
+		for j, id := range preset.Parameters {
+			if ParameterByID(id, config.Parameters) == nil {
+				report.errorf(fmt.Sprintf("%s.parameters[%d]", path, j), "unknown parameter '%s'", id)
+			}
+		}
+		for j, id := range preset.RemoveParameters {
+			if ParameterByID(id, config.Parameters) == nil {
+				report.warnf(fmt.Sprintf("%s.remove_parameters[%d]", path, j), "unknown parameter '%s'", id)
+			}
+		}
+		if err := ValidateWorkDir(preset.WorkDir); err != nil {
+			report.errorf(path+".work_dir", "%v", err)
+		}
+		for key := range preset.Env {
+			if strings.TrimSpace(key) == "" || strings.ContainsAny(key, "= \t") {
+				report.errorf(path+".env", "invalid variable name '%s'", key)
+			}
+		}
+
+		// Inheritance and the rules of the effective parameter set
+		if preset.Extends != "" && PresetByID(preset.Extends, config.Presets) == nil {
+			report.errorf(path+".extends", "unknown preset '%s'", preset.Extends)
+			continue
+		}
+		resolved, err := ResolvePreset(preset, config.Presets)
+		if err != nil {
+			report.errorf(path+".extends", "%v", err)
+			continue
+		}
+		if resolved.Platform == "" {
+			report.warnf(path+".platform", "no platform set, mobile is used")
+		}
+		// Unknown parameters are reported above, rules are only checked for known ones
+		known := true
+		for _, id := range resolved.Parameters {
+			if ParameterByID(id, config.Parameters) == nil {
+				known = false
+			}
+		}
+		if known {
+			if err := ValidatePreset(resolved, config.Parameters); err != nil {
+				report.errorf(path+".parameters", "%v", err)
+			}
+		}
+	}
+}
+
+// reportHygiene warns about issues that do not break anything but are likely mistakes
+func reportHygiene(config *Config, report *ValidationReport) {
+	used := make(map[string]bool)
+	for _, preset := range config.Presets {
+		for _, id := range preset.Parameters {
+			used[id] = true
+		}
+	}
+	for _, param := range config.Parameters {
+		for _, id := range param.Requires {
+			used[id] = true
+		}
+	}
+
+	envVars := make(map[string]int)
+	for i, param := range config.Parameters {
+		path := fmt.Sprintf("$.parameters[%d]", i)
+		if len(config.Presets) > 0 && !used[param.ID] {
+			report.warnf(path, "parameter '%s' is not used by any preset", param.Name)
+		}
+
+		name := param.EnvVar
+		if !param.Secret {
+			name, _, _ = strings.Cut(param.EnvVar, "=")
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if first, ok := envVars[name]; ok {
+			report.warnf(path+".env_var", "variable %s is also set by $.parameters[%d]", name, first)
+		} else {
+			envVars[name] = i
+		}
+	}
+
+	for i, preset := range config.Presets {
+		resolved, err := ResolvePreset(preset, config.Presets)
+		if err != nil {
+			continue
+		}
+		if len(resolved.Parameters) == 0 && len(resolved.Env) == 0 && len(resolved.Args) == 0 {
+			report.warnf(fmt.Sprintf("$.presets[%d]", i), "preset '%s' sets no parameters, env overrides or arguments", preset.Name)
+		}
+	}
+}
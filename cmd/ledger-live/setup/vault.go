@@ -0,0 +1,306 @@
+package setup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"golang.org/x/crypto/scrypt"
+)
+
+// SecretMask replaces secret values in every display
+const SecretMask = "****"
+
+// Vault file name, stored next to the config file
+const vaultFileName = "secrets.vault"
+
+// Environment variable that provides the vault passphrase without prompting
+const vaultPassphraseEnv = "LEDGER_LIVE_STARTER_VAULT_PASSPHRASE"
+
+// scrypt parameters recommended for interactive logins
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	vaultKeySize = 32
+)
+
+// vaultFile is the on-disk format: secrets are JSON encoded, then sealed with AES-256-GCM
+// using a key derived from the passphrase with scrypt
+type vaultFile struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ErrWrongPassphrase is returned when the vault cannot be decrypted
+var ErrWrongPassphrase = errors.New("wrong vault passphrase")
+
+// Passphrase cached for the lifetime of the process so the user is asked once
+var vaultPassphrase string
+
+// GetVaultPath returns the path of the encrypted secrets file
+func GetVaultPath() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), vaultFileName)
+}
+
+// VaultExists checks if the encrypted secrets file exists
+func VaultExists() bool {
+	_, err := os.Stat(GetVaultPath())
+	return err == nil
+}
+
+// MaskedEnvVar returns the env var of a parameter as it may be shown to the user
+func MaskedEnvVar(param Parameter) string {
+	if param.Secret {
+		return SecretEnvVarName(param) + "=" + SecretMask
+	}
+	return param.EnvVar
+}
+
+// SecretEnvVarName returns the variable name of a secret parameter
+func SecretEnvVarName(param Parameter) string {
+	name, _, _ := strings.Cut(param.EnvVar, "=")
+	return strings.TrimSpace(name)
+}
+
+// Secret values are keyed by parameter ID. Vaults written before IDs were used key them
+// by parameter name; those values are still read and move to the ID on the next write.
+
+// SetSecret stores or replaces the value of a secret parameter
+func SetSecret(param Parameter, value string) error {
+	return updateVault(func(secrets map[string]string) {
+		delete(secrets, param.Name)
+		secrets[param.ID] = value
+	})
+}
+
+// DeleteSecrets removes the values of the given secret parameters
+func DeleteSecrets(params []Parameter) error {
+	if !VaultExists() {
+		return nil
+	}
+	return updateVault(func(secrets map[string]string) {
+		for _, param := range params {
+			delete(secrets, param.ID)
+			delete(secrets, param.Name)
+		}
+	})
+}
+
+// MigrateSecretKey moves a value stored under the parameter's name to its ID. It must
+// run before the parameter is renamed, while the old name still finds the value.
+func MigrateSecretKey(param Parameter) error {
+	if !VaultExists() {
+		return nil
+	}
+	secrets, err := unlockVault()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[param.Name]; !ok {
+		return nil
+	}
+	return updateVault(func(secrets map[string]string) {
+		if value, ok := secrets[param.Name]; ok {
+			delete(secrets, param.Name)
+			if _, exists := secrets[param.ID]; !exists {
+				secrets[param.ID] = value
+			}
+		}
+	})
+}
+
+// updateVault applies a change to the secrets while holding the vault lock.
+// The passphrase is asked for before locking so a prompt never blocks other instances.
+func updateVault(change func(secrets map[string]string)) error {
+	if _, err := unlockVault(); err != nil {
+		return err
+	}
+	return WithFileLock(GetVaultPath(), func() error {
+		secrets, err := unlockVault()
+		if err != nil {
+			return err
+		}
+		change(secrets)
+		return writeVault(secrets)
+	})
+}
+
+// ResolveSecrets decrypts the values for a set of env var -> parameter references.
+// It is only meant to be called when building the child process environment.
+func ResolveSecrets(refs map[string]Parameter) (map[string]string, error) {
+	values := make(map[string]string)
+	if len(refs) == 0 {
+		return values, nil
+	}
+	if !VaultExists() {
+		return nil, fmt.Errorf("no secrets vault found at %s", GetVaultPath())
+	}
+
+	secrets, err := unlockVault()
+	if err != nil {
+		return nil, err
+	}
+	for envVar, param := range refs {
+		value, ok := secrets[param.ID]
+		if !ok {
+			value, ok = secrets[param.Name]
+		}
+		if !ok {
+			return nil, fmt.Errorf("no secret value stored for parameter '%s'", param.Name)
+		}
+		values[envVar] = value
+	}
+	return values, nil
+}
+
+// unlockVault decrypts the vault, prompting for the passphrase if needed.
+// A missing vault is treated as empty and gets created on the next write.
+func unlockVault() (map[string]string, error) {
+	creating := !VaultExists()
+	if vaultPassphrase == "" {
+		passphrase, err := getVaultPassphrase(creating)
+		if err != nil {
+			return nil, err
+		}
+		vaultPassphrase = passphrase
+	}
+
+	if creating {
+		return make(map[string]string), nil
+	}
+
+	secrets, err := readVault(vaultPassphrase)
+	if err != nil {
+		// Forget a wrong passphrase so the next attempt prompts again
+		vaultPassphrase = ""
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// getVaultPassphrase reads the passphrase from the environment or prompts for it
+func getVaultPassphrase(creating bool) (string, error) {
+	if passphrase := os.Getenv(vaultPassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	var passphrase, confirmation string
+	fields := []huh.Field{
+		huh.NewInput().
+			Title("Vault passphrase:").
+			Description("Secret parameter values are encrypted with this passphrase.").
+			EchoMode(huh.EchoModePassword).
+			Value(&passphrase).
+			Validate(func(s string) error {
+				if s == "" {
+					return fmt.Errorf("passphrase cannot be empty")
+				}
+				return nil
+			}),
+	}
+	if creating {
+		fields = append(fields, huh.NewInput().
+			Title("Confirm passphrase:").
+			EchoMode(huh.EchoModePassword).
+			Value(&confirmation).
+			Validate(func(s string) error {
+				if s != passphrase {
+					return fmt.Errorf("passphrases do not match")
+				}
+				return nil
+			}))
+	}
+
+	err := RunStyledForm(huh.NewForm(huh.NewGroup(fields...)))
+	if err != nil {
+		return "", fmt.Errorf("vault passphrase input cancelled")
+	}
+	return passphrase, nil
+}
+
+// readVault decrypts the vault file with a passphrase
+func readVault(passphrase string) (map[string]string, error) {
+	data, err := os.ReadFile(GetVaultPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault: %v", err)
+	}
+
+	var file vaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse vault: %v", err)
+	}
+
+	gcm, err := vaultCipher(passphrase, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse vault contents: %v", err)
+	}
+	return secrets, nil
+}
+
+// writeVault encrypts the secrets with the cached passphrase, using a fresh salt and nonce
+func writeVault(secrets map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := vaultCipher(vaultPassphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(vaultFile{
+		Version:    1,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(GetVaultPath()), 0755); err != nil {
+		return err
+	}
+	return WriteFileAtomic(GetVaultPath(), data, 0600)
+}
+
+// vaultCipher derives the AES-GCM cipher for a passphrase and salt
+func vaultCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, vaultKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/huh"
-
+	"ledger-live-starter/cmd/ledger-live/parameters"
+	"ledger-live-starter/cmd/ledger-live/presets"
+	"ledger-live-starter/cmd/ledger-live/setup"
 )
 
 // Shared UI functions that can be reused across different flows
@@ -42,22 +45,19 @@ func selectPlatform() (string, string, error) {
 	}
 }
 
-func selectParameters(availableParams []Parameter) ([]Parameter, error) {
-	// Build options for huh multi-select
-	var options []huh.Option[string]
-	for _, param := range availableParams {
-		options = append(options, huh.NewOption(param.Name, param.Name))
-	}
+func selectParameters(availableParams []Parameter, platform string) ([]Parameter, error) {
+	var selectedIDs []string
 
-	var selectedNames []string
+	// Searchable, category grouped list of the parameters for the chosen platform
+	search, picker := parameters.NewParameterPicker(availableParams, &platform, &selectedIDs, "Choose parameters:")
 	
 	form := huh.NewForm(
 		huh.NewGroup(
-			huh.NewMultiSelect[string]().
-				Title("Choose parameters:").
-				Description("Select the parameters you want to use."). // make dynamic based on selected option
-				Options(options...).
-				Value(&selectedNames),
+			search,
+			picker.Validate(func(ids []string) error {
+				_, _, err := setup.ResolveParameterSelection(ids, availableParams, platform)
+				return err
+			}),
 		),
 	)
 
@@ -66,14 +66,26 @@ func selectParameters(availableParams []Parameter) ([]Parameter, error) {
 		return []Parameter{}, fmt.Errorf("parameter selection cancelled")
 	}
 
-	// Convert selected names back to Parameter structs
+	return resolveSelectedParameters(selectedIDs, availableParams, platform)
+}
+
+// resolveSelectedParameters adds required parameters to a selection and converts
+// the IDs back to Parameter structs
+func resolveSelectedParameters(selectedIDs []string, availableParams []Parameter, platform string) ([]Parameter, error) {
+	resolvedIDs, addedIDs, err := setup.ResolveParameterSelection(selectedIDs, availableParams, platform)
+	if err != nil {
+		return []Parameter{}, err
+	}
+
+	if len(addedIDs) > 0 {
+		fmt.Printf("%s %s %s\n", InfoTextTitle("Info:"), NormalText("Added required parameters:"), HighlightText(strings.Join(setup.ParameterNames(addedIDs, availableParams), ", ")))
+	}
+
+	// Convert selected IDs back to Parameter structs
 	var selectedParams []Parameter
-	for _, selectedName := range selectedNames {
-		for _, param := range availableParams {
-			if param.Name == selectedName {
-				selectedParams = append(selectedParams, param)
-				break
-			}
+	for _, id := range resolvedIDs {
+		if param := setup.ParameterByID(id, availableParams); param != nil {
+			selectedParams = append(selectedParams, *param)
 		}
 	}
 
@@ -90,15 +102,7 @@ func inputPresetName(existingPresets []Preset) (string, error) {
 				Placeholder("e.g., 'Mobile Dev', 'Desktop Testing'").
 				Value(&name).
 				Validate(func(s string) error {
-					if strings.TrimSpace(s) == "" {
-						return fmt.Errorf("preset name cannot be empty")
-					}
-					for _, p := range existingPresets {
-						if p.Name == s {
-							return fmt.Errorf("preset '%s' already exists", s)
-						}
-					}
-					return nil
+					return presets.ValidatePresetName(s, existingPresets, "")
 				}),
 		),
 	)
@@ -144,30 +148,19 @@ func selectPlatformWithDefault(defaultPlatform string) (string, string, error) {
 	}
 }
 
-func selectParametersWithDefault(availableParams []Parameter, selectedParameterNames []string) ([]Parameter, error) {
-	// Build options for huh multi-select
-	var options []huh.Option[string]
-	for _, param := range availableParams {
-		option := huh.NewOption(param.Name, param.Name)
-		// Check if this parameter should be preselected
-		for _, selectedName := range selectedParameterNames {
-			if param.Name == selectedName {
-				option = option.Selected(true)
-				break
-			}
-		}
-		options = append(options, option)
-	}
+func selectParametersWithDefault(availableParams []Parameter, selectedParameterIDs []string, platform string) ([]Parameter, error) {
+	// Preselect the current parameters
+	selectedIDs := append([]string{}, selectedParameterIDs...)
 
-	var selectedNames []string
+	search, picker := parameters.NewParameterPicker(availableParams, &platform, &selectedIDs, "Choose parameters:")
 	
 	form := huh.NewForm(
 		huh.NewGroup(
-			huh.NewMultiSelect[string]().
-				Title("Choose parameters:").
-				Description("Select additional parameters.").
-				Options(options...).
-				Value(&selectedNames),
+			search,
+			picker.Validate(func(ids []string) error {
+				_, _, err := setup.ResolveParameterSelection(ids, availableParams, platform)
+				return err
+			}),
 		),
 	)
 
@@ -176,18 +169,7 @@ func selectParametersWithDefault(availableParams []Parameter, selectedParameterN
 		return []Parameter{}, fmt.Errorf("parameter selection cancelled")
 	}
 
-	// Convert selected names back to Parameter structs
-	var selectedParams []Parameter
-	for _, selectedName := range selectedNames {
-		for _, param := range availableParams {
-			if param.Name == selectedName {
-				selectedParams = append(selectedParams, param)
-				break
-			}
-		}
-	}
-
-	return selectedParams, nil
+	return resolveSelectedParameters(selectedIDs, availableParams, platform)
 }
 
 func inputPresetNameWithDefault(existingPresets []Preset, currentName string) (string, error) {
@@ -200,19 +182,7 @@ func inputPresetNameWithDefault(existingPresets []Preset, currentName string) (s
 				Placeholder("e.g., 'Mobile Dev', 'Desktop Testing'").
 				Value(&name).
 				Validate(func(s string) error {
-					if strings.TrimSpace(s) == "" {
-						return fmt.Errorf("preset name cannot be empty")
-					}
-					// Allow keeping the same name
-					if s == currentName {
-						return nil
-					}
-					for _, p := range existingPresets {
-						if p.Name == s {
-							return fmt.Errorf("preset '%s' already exists", s)
-						}
-					}
-					return nil
+					return presets.ValidatePresetName(s, existingPresets, currentName)
 				}),
 		),
 	)
@@ -224,3 +194,33 @@ func inputPresetNameWithDefault(existingPresets []Preset, currentName string) (s
 	
 	return strings.TrimSpace(name), nil
 }
+
+// exitWithError prints an error and exits with a non-zero status
+func exitWithError(err error) {
+	fmt.Fprintf(diagnostics, "%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+	os.Exit(1)
+}
+
+// loadConfigOrExit loads the config for commands that cannot fall back to defaults
+func loadConfigOrExit() *Config {
+	// Interactive setup would mix its prompts into a machine-readable document
+	if diagnostics == os.Stderr && !setup.ConfigExists() {
+		exitWithError(fmt.Errorf("no configuration found, run 'ledger-live setup' first"))
+	}
+	config, err := setup.LoadConfig()
+	if err != nil {
+		exitWithError(err)
+	}
+	return config
+}
+
+// nonEmpty drops empty values, so a list flag given as "" clears the list
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			result = append(result, strings.TrimSpace(value))
+		}
+	}
+	return result
+}
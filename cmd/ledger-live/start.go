@@ -20,14 +20,34 @@ var startCmd = &cobra.Command{
 	Run:   runStartCmd,
 }
 
+// Menu values for the "Run last preset" entry and group sections
+const (
+	lastPresetOption  = "__last_preset__"
+	groupOptionPrefix = "__group__:"
+)
+
+// Preset to run directly, skipping the menu
+var startPreset string
+
 func init() {
+	startCmd.Flags().StringVar(&startPreset, "preset", "", "run this preset without showing the menu")
+	startCmd.Flags().BoolVar(&noUpdateCheck, "no-update-check", false, "do not check for a new version of the starter")
+	startCmd.RegisterFlagCompletionFunc("preset", completePresetNames)
 	rootCmd.AddCommand(startCmd)
 }
 
 func runStartCmd(cmd *cobra.Command, args []string) {
+	// The menu needs a terminal, so fail before drawing anything
+	if startPreset == "" {
+		requireTerminal()
+	}
+
 	fmt.Println(ui.GetLogo())
 	fmt.Println()
 	fmt.Println(getVersionOrUpdateDisplay())
+	if profile := setup.ActiveProfile(); profile != "" {
+		fmt.Printf("    %s %s\n", InfoTextTitle("Profile:"), HighlightText(profile))
+	}
 	fmt.Println()
 	
 	// Load configuration
@@ -37,6 +57,20 @@ func runStartCmd(cmd *cobra.Command, args []string) {
 		config = setup.GetDefaultConfig()
 	}
 
+	// Point out references broken by hand edits of the config
+	for _, problem := range setup.DanglingParameterReferences(config) {
+		fmt.Printf("%s %s\n", WarningText("Warning:"), NormalText(problem))
+	}
+
+	// Run the preset given with --preset directly
+	if startPreset != "" {
+		if setup.FindPreset(startPreset, config.Presets) == nil {
+			exitWithError(fmt.Errorf("preset '%s' not found", startPreset))
+		}
+		executePreset(startPreset, config)
+		return
+	}
+
 	// Show main menu based on preset availability
 	if len(config.Presets) > 0 {
 		showPresetMenu(config)
@@ -47,10 +81,28 @@ func runStartCmd(cmd *cobra.Command, args []string) {
 
 func showPresetMenu(config *Config) {
 	var options []huh.Option[string]
+	hotkeys := make(map[string]string)
+	usage := setup.LoadUsage()
+
+	// Offer the last started preset first so the daily launch is a single ENTER
+	lastPreset := usage.LastPresetIn(config.Presets)
+	if lastPreset != nil {
+		options = append(options, huh.NewOption(fmt.Sprintf("↻ Run last preset (%s)", lastPreset.Name), lastPresetOption))
+	}
 	
-	// Add presets with default styling
+	// Add favorites and ungrouped presets, favorites first and the rest by recent use
+	var topLevel []Preset
 	for _, preset := range config.Presets {
-		options = append(options, huh.NewOption(preset.Name, preset.Name))
+		if preset.Favorite || strings.TrimSpace(preset.Group) == "" {
+			topLevel = append(topLevel, preset)
+		}
+	}
+	options = append(options, createPresetMenuOptions(setup.SortPresetsForMenu(topLevel, usage), hotkeys)...)
+
+	// Add one entry per group, opening the group's presets
+	for _, group := range setup.PresetGroups(config.Presets) {
+		count := len(setup.PresetsInGroup(group, config.Presets))
+		options = append(options, huh.NewOption(fmt.Sprintf("▸ %s (%d)", group, count), groupOptionPrefix+group))
 	}
 	
 	// Add standard options
@@ -64,25 +116,31 @@ func showPresetMenu(config *Config) {
 		huh.NewGroup(
 			huh.NewSelect[string]().
 				Title("Choose an option:").
-				Description("Select a preset to start the application directly."). // make dynamic based on selected option
+				DescriptionFunc(func() string {
+					return describePresetOption(selected, config)
+				}, &selected).
 				Options(options...).
 				Value(&selected),
 		),
 	)
 
-	err := RunStyledForm(form)
+	err := RunStyledFormWithHotkeys(form, hotkeys, &selected)
 	if err != nil {
 		ShowCancellationMessage()
 		return
 	}
 
 	// Handle selection
-	switch selected {
-	case "manual":
+	switch {
+	case selected == lastPresetOption:
+		executePreset(lastPreset.Name, config)
+	case strings.HasPrefix(selected, groupOptionPrefix):
+		showPresetGroupMenu(strings.TrimPrefix(selected, groupOptionPrefix), config)
+	case selected == "manual":
 		startManually()
-	case "more":
+	case selected == "more":
 		showMoreMenu(config)
-	case "exit":
+	case selected == "exit":
 		ShowGoodbyeMessage()
 		return
 	default:
@@ -91,6 +149,72 @@ func showPresetMenu(config *Config) {
 	}
 }
 
+// showPresetGroupMenu lists the presets of one group
+func showPresetGroupMenu(group string, config *Config) {
+	hotkeys := make(map[string]string)
+	usage := setup.LoadUsage()
+
+	options := createPresetMenuOptions(setup.SortPresetsForMenu(setup.PresetsInGroup(group, config.Presets), usage), hotkeys)
+	options = append(options, huh.NewOption("Back", "back"))
+
+	var selected string
+	
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("%s presets:", group)).
+				DescriptionFunc(func() string {
+					return describePresetOption(selected, config)
+				}, &selected).
+				Options(options...).
+				Value(&selected),
+		),
+	)
+
+	err := RunStyledFormWithHotkeys(form, hotkeys, &selected)
+	if err != nil {
+		ShowCancellationMessage()
+		return
+	}
+
+	if selected == "back" {
+		showPresetMenu(config)
+		return
+	}
+	executePreset(selected, config)
+}
+
+// describePresetOption returns the note of the highlighted preset, or the default menu help
+func describePresetOption(value string, config *Config) string {
+	if value == lastPresetOption {
+		if preset := setup.LoadUsage().LastPresetIn(config.Presets); preset != nil {
+			value = preset.Name
+		}
+	}
+	if preset := setup.FindPreset(value, config.Presets); preset != nil && preset.Note != "" {
+		return preset.Note
+	}
+	return "Select a preset to start the application directly, or press 1-9."
+}
+
+// createPresetMenuOptions builds start menu entries, numbering the first nine as hotkeys
+func createPresetMenuOptions(sorted []Preset, hotkeys map[string]string) []huh.Option[string] {
+	var options []huh.Option[string]
+	for i, preset := range sorted {
+		label := preset.Name
+		if preset.Favorite {
+			label = "★ " + label
+		}
+		if i < 9 {
+			hotkey := fmt.Sprintf("%d", i+1)
+			hotkeys[hotkey] = preset.Name
+			label = fmt.Sprintf("%s  %s", hotkey, label)
+		}
+		options = append(options, huh.NewOption(label, preset.Name))
+	}
+	return options
+}
+
 func showNoPresetMenu() {
 	var options []huh.Option[string]
 	options = append(options, huh.NewOption("Create preset", "create"))
@@ -143,14 +267,44 @@ func executePreset(presetName string, config *Config) {
 		return
 	}
 
+	// Apply inheritance before validating
+	effectivePreset, err := setup.ResolvePreset(*selectedPreset, config.Presets)
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("❌"), NormalText(err.Error()))
+		return
+	}
+
+	// Refuse presets that break their parameters' platform, requirement or conflict rules
+	if err := setup.ValidatePreset(effectivePreset, config.Parameters); err != nil {
+		fmt.Printf("%s %s\n", ErrorText("❌"), NormalText(err.Error()))
+		fmt.Printf("%s %s\n", InfoTextTitle("Info:"), NormalText("Edit the preset via More > Edit presets to fix it."))
+		return
+	}
+
 	// Convert preset to command
-	cmdInfo := buildPresetCommand(selectedPreset, config)
+	cmdInfo, err := buildPresetCommand(selectedPreset, config)
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("❌"), NormalText(err.Error()))
+		return
+	}
+	
+	// Remember the run for menu ordering and "Run last preset"
+	if err := setup.RecordPresetRun(*selectedPreset); err != nil {
+		fmt.Printf("%s %s\n", WarningText("Warning:"), NormalText(fmt.Sprintf("Could not record preset usage (%v)", err)))
+	}
 	
 	fmt.Printf("%s %s %s\n", SuccessText("✅"), NormalText("Starting preset:"), HighlightText(selectedPreset.Name))
 	executeCommand(cmdInfo)
 }
 
-func buildPresetCommand(preset *Preset, config *Config) *CommandInfo {
+func buildPresetCommand(preset *Preset, config *Config) (*CommandInfo, error) {
+	// Apply the preset's extends chain
+	effectivePreset, err := setup.ResolvePreset(*preset, config.Presets)
+	if err != nil {
+		return nil, err
+	}
+	preset = &effectivePreset
+
 	// Determine base command from platform
 	var baseCommand string
 	switch preset.Platform {
@@ -162,28 +316,26 @@ func buildPresetCommand(preset *Preset, config *Config) *CommandInfo {
 		baseCommand = "pnpm dev:llm" // default to mobile
 	}
 
-	// Find and parse parameter env vars
-	envVars := make(map[string]string)
-	for _, paramName := range preset.Parameters {
-		for _, param := range config.Parameters {
-			if param.Name == paramName {
-				// Parse "VAR_NAME=value" format
-				if strings.Contains(param.EnvVar, "=") {
-					parts := strings.SplitN(param.EnvVar, "=", 2)
-					if len(parts) == 2 {
-						envVars[parts[0]] = parts[1]
-					}
-				}
-				break
-			}
+	// Find the preset's parameters, refusing to silently drop missing ones
+	var presetParams []Parameter
+	for _, id := range preset.Parameters {
+		param := setup.ParameterByID(id, config.Parameters)
+		if param == nil {
+			return nil, fmt.Errorf("preset '%s' references unknown parameter '%s'", preset.Name, id)
 		}
+		presetParams = append(presetParams, *param)
 	}
 
-	return &CommandInfo{
-		BaseCommand: baseCommand,
-		EnvVars:     envVars,
-		WorkingDir:  config.LedgerLivePath,
+	if err := setup.ValidateWorkDir(preset.WorkDir); err != nil {
+		return nil, err
 	}
+
+	cmdInfo, err := buildCommandWithOverrides(baseCommand, preset.Name, presetParams, preset.Env, setup.PresetWorkingDir(*preset, config.LedgerLivePath), config)
+	if err != nil {
+		return nil, err
+	}
+	cmdInfo.Args = preset.Args
+	return cmdInfo, nil
 }
 
 func showMoreMenu(config *Config) {
@@ -237,15 +389,9 @@ func showMoreMenu(config *Config) {
 // Deprecated: Use getVersionOrUpdateDisplay() instead
 // Kept for backward compatibility, but now just shows current version
 func getVersionDisplay() string {
-	// Define adaptive text color (same as theme.go)
-	textColor := lipgloss.AdaptiveColor{
-		Light: "#1a1a1a", // Dark text for light backgrounds
-		Dark:  "#ffffff", // White text for dark backgrounds
-	}
-	
 	// Create version text with adaptive color and center alignment
 	versionStyle := lipgloss.NewStyle().
-		Foreground(textColor).
+		Foreground(palette.Text).
 		Align(lipgloss.Center).
 		MarginLeft(4) // Same left margin as logo
 	
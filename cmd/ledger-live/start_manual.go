@@ -22,14 +22,18 @@ func startManually() {
 	}
 
 	// Step 2: Parameter selection
-	selectedParams, err := selectParameters(config.Parameters)
+	selectedParams, err := selectParameters(config.Parameters, setup.PlatformKey(baseCommand))
 	if err != nil {
 		fmt.Printf("%s %v\n", ErrorText("Error:"), NormalText(err.Error()))
 		return
 	}
 
 	// Step 3: Build and execute command
-	cmdInfo := buildCommand(baseCommand, selectedParams, config)
+	cmdInfo, err := buildCommand(baseCommand, "", selectedParams, config)
+	if err != nil {
+		fmt.Printf("%s %s\n", ErrorText("Error:"), NormalText(err.Error()))
+		return
+	}
 	fmt.Printf("\n%s %s %s...\n", SuccessText("Success:"), NormalText("Starting"), HighlightText(platform))
 	executeCommand(cmdInfo)
 }
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"os"
+	"strconv"
+	"strings"
+
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"ledger-live-starter/cmd/ledger-live/setup"
 )
 
 const (
@@ -10,45 +16,168 @@ const (
 	defaultMarginLeft = 4
 )
 
-// GetCustomTheme returns a complete custom theme for huh forms
-// Based on the official huh theme structure with our custom colors and styling
-func GetCustomTheme() *huh.Theme {
-	// Define adaptive colors for consistent theming across light/dark terminals
-	primaryColor := lipgloss.AdaptiveColor{
-		Light: "#7200c9", // Deep purple for light backgrounds
-		Dark:  "#9d4edd", // Lighter purple for dark backgrounds  
+// Set with --no-color
+var noColor bool
+
+// Palette holds the colors of a theme, each for light and dark terminal backgrounds.
+// Empty colors render without color.
+type Palette struct {
+	Primary       lipgloss.AdaptiveColor // Titles, cursors and borders
+	Muted         lipgloss.AdaptiveColor // Descriptions and blurred fields
+	Text          lipgloss.AdaptiveColor
+	Highlight     lipgloss.AdaptiveColor // Selected options and buttons
+	Error         lipgloss.AdaptiveColor
+	Success       lipgloss.AdaptiveColor
+	Info          lipgloss.AdaptiveColor
+	Warning       lipgloss.AdaptiveColor
+	GradientStart lipgloss.AdaptiveColor // Logo and box borders, left end
+	GradientEnd   lipgloss.AdaptiveColor // Logo and box borders, right end
+}
+
+// builtinPalettes are the themes selectable by name in the config
+var builtinPalettes = map[string]Palette{
+	// Purple to orange brand colors
+	setup.ThemeDefault: {
+		Primary:       lipgloss.AdaptiveColor{Light: "#7200c9", Dark: "#9d4edd"},
+		Muted:         lipgloss.AdaptiveColor{Light: "#666666", Dark: "#888888"},
+		Text:          lipgloss.AdaptiveColor{Light: "#1a1a1a", Dark: "#ffffff"},
+		Highlight:     lipgloss.AdaptiveColor{Light: "#d85500", Dark: "#f2830c"},
+		Error:         lipgloss.AdaptiveColor{Light: "#dc2626", Dark: "#ef4444"},
+		Success:       lipgloss.AdaptiveColor{Light: "#16a34a", Dark: "#22c55e"},
+		Info:          lipgloss.AdaptiveColor{Light: "#0369a1", Dark: "#38bdf8"},
+		Warning:       lipgloss.AdaptiveColor{Light: "#d97706", Dark: "#fbbf24"},
+		GradientStart: lipgloss.AdaptiveColor{Light: "#7200c9", Dark: "#7200c9"},
+		GradientEnd:   lipgloss.AdaptiveColor{Light: "#f2830c", Dark: "#f2830c"},
+	},
+	// Strong contrast on both backgrounds, and blue/orange instead of green/red so
+	// success and errors stay apart with color-vision deficiencies
+	setup.ThemeHighContrast: {
+		Primary:       lipgloss.AdaptiveColor{Light: "#00008b", Dark: "#87d7ff"},
+		Muted:         lipgloss.AdaptiveColor{Light: "#303030", Dark: "#d0d0d0"},
+		Text:          lipgloss.AdaptiveColor{Light: "#000000", Dark: "#ffffff"},
+		Highlight:     lipgloss.AdaptiveColor{Light: "#5f00af", Dark: "#ffff5f"},
+		Error:         lipgloss.AdaptiveColor{Light: "#a33c00", Dark: "#ff9e4a"},
+		Success:       lipgloss.AdaptiveColor{Light: "#00539c", Dark: "#5fd7ff"},
+		Info:          lipgloss.AdaptiveColor{Light: "#00008b", Dark: "#87d7ff"},
+		Warning:       lipgloss.AdaptiveColor{Light: "#6b4a00", Dark: "#ffd75f"},
+		GradientStart: lipgloss.AdaptiveColor{Light: "#000000", Dark: "#ffffff"},
+		GradientEnd:   lipgloss.AdaptiveColor{Light: "#000000", Dark: "#ffffff"},
+	},
+	// No colors at all, only bold text
+	setup.ThemeMonochrome: {},
+}
+
+// Colors of the active theme
+var palette = builtinPalettes[setup.ThemeDefault]
+
+// applyTheme activates the theme from the config, or no colors at all with --no-color
+// or NO_COLOR (https://no-color.org)
+func applyTheme() {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		palette = builtinPalettes[setup.ThemeMonochrome]
+		return
+	}
+
+	config, _ := setup.PeekConfig()
+	if config == nil || config.Theme == nil {
+		return
 	}
-	
-	titleBlurredColor := lipgloss.AdaptiveColor{
-		Light: "#666666", // Dark gray for light backgrounds
-		Dark:  "#888888", // Light gray for dark backgrounds
+	// Unknown names and colors are reported by 'config validate'
+	if builtin, ok := builtinPalettes[config.Theme.Name]; ok {
+		palette = builtin
 	}
-	
-	textColor := lipgloss.AdaptiveColor{
-		Light: "#1a1a1a", // Dark text for light backgrounds
-		Dark:  "#ffffff", // White text for dark backgrounds
+	for role, color := range config.Theme.Colors {
+		if target := palette.color(role); target != nil {
+			*target = themeColor(color)
+		}
 	}
+}
 
-	descriptionColor := lipgloss.AdaptiveColor{
-		Light: "#666666", // Dark gray for light backgrounds
-		Dark:  "#888888", // Light gray for dark backgrounds
+// color returns the palette entry for a setup.ThemeColorRoles role
+func (p *Palette) color(role string) *lipgloss.AdaptiveColor {
+	switch role {
+	case "primary":
+		return &p.Primary
+	case "muted":
+		return &p.Muted
+	case "text":
+		return &p.Text
+	case "highlight":
+		return &p.Highlight
+	case "error":
+		return &p.Error
+	case "success":
+		return &p.Success
+	case "info":
+		return &p.Info
+	case "warning":
+		return &p.Warning
+	case "gradient_start":
+		return &p.GradientStart
+	case "gradient_end":
+		return &p.GradientEnd
 	}
-	
-	highlightColor := lipgloss.AdaptiveColor{
-		Light: "#d85500", // Darker orange for light backgrounds (better contrast)
-		Dark:  "#f2830c", // Bright orange for dark backgrounds (matches gradient end)
+	return nil
+}
+
+// themeColor converts a config color, using a single given color for both backgrounds
+func themeColor(color setup.ThemeColor) lipgloss.AdaptiveColor {
+	if color.Light == "" {
+		color.Light = color.Dark
 	}
-	
-	errorColor := lipgloss.AdaptiveColor{
-		Light: "#dc2626", // Red for light backgrounds
-		Dark:  "#ef4444", // Lighter red for dark backgrounds
+	if color.Dark == "" {
+		color.Dark = color.Light
 	}
-	
-	successColor := lipgloss.AdaptiveColor{
-		Light: "#16a34a", // Green for light backgrounds
-		Dark:  "#22c55e", // Lighter green for dark backgrounds
+	return lipgloss.AdaptiveColor{Light: color.Light, Dark: color.Dark}
+}
+
+// brandGradient returns the logo and box border gradient of the active theme, ok is false
+// when the theme has none
+func brandGradient() (start, end [3]int, ok bool) {
+	pick := func(color lipgloss.AdaptiveColor) string {
+		if lipgloss.HasDarkBackground() {
+			return color.Dark
+		}
+		return color.Light
 	}
-	
+	start, startOK := parseHexColor(pick(palette.GradientStart))
+	end, endOK := parseHexColor(pick(palette.GradientEnd))
+	return start, end, startOK && endOK
+}
+
+// parseHexColor reads a #rgb or #rrggbb color
+func parseHexColor(value string) ([3]int, bool) {
+	var rgb [3]int
+	value = strings.TrimPrefix(value, "#")
+	if len(value) == 3 {
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	}
+	if len(value) != 6 {
+		return rgb, false
+	}
+	for i := range rgb {
+		component, err := strconv.ParseUint(value[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return rgb, false
+		}
+		rgb[i] = int(component)
+	}
+	return rgb, true
+}
+
+// GetCustomTheme returns a complete custom theme for huh forms
+// Based on the official huh theme structure with our custom colors and styling
+func GetCustomTheme() *huh.Theme {
+	// Colors of the active theme, see applyTheme
+	primaryColor := palette.Primary
+	titleBlurredColor := palette.Muted
+	textColor := palette.Text
+	descriptionColor := palette.Muted
+	highlightColor := palette.Highlight
+	errorColor := palette.Error
+	successColor := palette.Success
+
 	// Create complete custom theme with all possible huh elements
 	return &huh.Theme{
 		Focused: huh.FieldStyles{
@@ -159,65 +288,39 @@ func GetCustomTheme() *huh.Theme {
 	}
 }
 
-// RunStyledForm applies our custom theme to a huh form and runs it
+// RunStyledForm applies our custom theme to a huh form and runs it, as line-based
+// prompts in accessible mode
 func RunStyledForm(form *huh.Form) error {
-	return form.WithTheme(GetCustomTheme()).Run()
+	requireTerminal()
+	return form.WithTheme(GetCustomTheme()).WithAccessible(accessibleMode()).Run()
 }
 
 // Theme-based text styling functions using consistent colors from theme
 func NormalText(text string) string {
-	textColor := lipgloss.AdaptiveColor{
-		Light: "#1a1a1a", // Dark text for light backgrounds
-		Dark:  "#ffffff", // White text for dark backgrounds
-	}
-	return lipgloss.NewStyle().Foreground(textColor).Render(text)
+	return lipgloss.NewStyle().Foreground(palette.Text).Render(text)
 }
 
 func TitleText(text string) string {
-	primaryColor := lipgloss.AdaptiveColor{
-		Light: "#7200c9", // Deep purple for light backgrounds
-		Dark:  "#9d4edd", // Lighter purple for dark backgrounds  
-	}
-	return lipgloss.NewStyle().Foreground(primaryColor).Bold(true).Render(text)
+	return lipgloss.NewStyle().Foreground(palette.Primary).Bold(true).Render(text)
 }
 
 func HighlightText(text string) string {
-	highlightColor := lipgloss.AdaptiveColor{
-		Light: "#d85500", // Darker orange for light backgrounds (better contrast)
-		Dark:  "#f2830c", // Bright orange for dark backgrounds (matches gradient end)
-	}
-	return lipgloss.NewStyle().Foreground(highlightColor).Render(text)
+	return lipgloss.NewStyle().Foreground(palette.Highlight).Render(text)
 }
 
 func SuccessText(text string) string {
-	successColor := lipgloss.AdaptiveColor{
-		Light: "#16a34a", // Green for light backgrounds
-		Dark:  "#22c55e", // Lighter green for dark backgrounds
-	}
-	return lipgloss.NewStyle().Foreground(successColor).Bold(true).Render(text)
+	return lipgloss.NewStyle().Foreground(palette.Success).Bold(true).Render(text)
 }
 
 func ErrorText(text string) string {
-	errorColor := lipgloss.AdaptiveColor{
-		Light: "#dc2626", // Red for light backgrounds
-		Dark:  "#ef4444", // Lighter red for dark backgrounds
-	}
-	return lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(text)
+	return lipgloss.NewStyle().Foreground(palette.Error).Bold(true).Render(text)
 }
 
 // Info text titles use a mid blue color
 func InfoTextTitle(text string) string {
-	infoTitleColor := lipgloss.AdaptiveColor{
-		Light: "#0369a1", // Mid blue for light backgrounds
-		Dark:  "#38bdf8", // Lighter blue for dark backgrounds
-	}
-	return lipgloss.NewStyle().Foreground(infoTitleColor).Render(text)
+	return lipgloss.NewStyle().Foreground(palette.Info).Render(text)
 }
 
 func WarningText(text string) string {
-	warningColor := lipgloss.AdaptiveColor{
-		Light: "#d97706", // Orange/amber for light backgrounds
-		Dark:  "#fbbf24", // Yellow for dark backgrounds
-	}
-	return lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render(text)
+	return lipgloss.NewStyle().Foreground(palette.Warning).Bold(true).Render(text)
 }
@@ -4,18 +4,18 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// TextColor is the color of text inside boxes. Set by the main package from the theme.
+var TextColor lipgloss.TerminalColor = lipgloss.AdaptiveColor{
+	Light: "#1a1a1a", // Dark text for light backgrounds
+	Dark:  "#ffffff", // White text for dark backgrounds
+}
+
 // CreateBoxWrapper creates a bordered box with gradient border (matching logo colors)
 func CreateBoxWrapper(content string) string {
-	// Define adaptive text color (same as theme.go)
-	textColor := lipgloss.AdaptiveColor{
-		Light: "#1a1a1a", // Dark text for light backgrounds
-		Dark:  "#ffffff", // White text for dark backgrounds
-	}
-	
 	// Create a basic bordered box with lipgloss for proper alignment
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		Foreground(textColor).    // Apply adaptive text color
+		Foreground(TextColor).    // Apply adaptive text color
 		Padding(1, 2).
 		Width(70).
 		Align(lipgloss.Center)
@@ -24,5 +24,9 @@ func CreateBoxWrapper(content string) string {
 	renderedBox := boxStyle.Render(content)
 	
 	// Apply brand gradient to border characters only
-	return ApplyGradientToBorderOnly(renderedBox, BrandStartColor, BrandEndColor)
+	start, end, ok := BrandGradient()
+	if !ok {
+		return renderedBox
+	}
+	return ApplyGradientToBorderOnly(renderedBox, start, end)
 }
@@ -11,6 +11,12 @@ var (
 	BrandEndColor   = [3]int{242, 131, 12} // Orange #f2830c
 )
 
+// BrandGradient returns the gradient of the active theme, ok is false to draw without
+// color. Set by the main package.
+var BrandGradient = func() (start, end [3]int, ok bool) {
+	return BrandStartColor, BrandEndColor, true
+}
+
 // GetGradientColor calculates a specific color from the gradient at a given progress (0.0 to 1.0)
 func GetGradientColor(progress float64, startColor [3]int, endColor [3]int) string {
 	r := int(float64(startColor[0]) + progress*float64(endColor[0]-startColor[0]))
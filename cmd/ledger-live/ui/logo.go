@@ -14,7 +14,10 @@ func GetLogo() string {
 ╚═════════════════════════════╝`
 	
 	// Apply brand gradient to the entire logo
-	gradientLogo := ApplyGradientToText(logo, BrandStartColor, BrandEndColor)
+	gradientLogo := logo
+	if start, end, ok := BrandGradient(); ok {
+		gradientLogo = ApplyGradientToText(logo, start, end)
+	}
 	
 	// Add left margin for better positioning
 	logoWithMargin := lipgloss.NewStyle().
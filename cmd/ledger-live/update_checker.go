@@ -4,17 +4,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"runtime"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"ledger-live-starter/cmd/ledger-live/setup"
 )
 
 // GitHubRelease represents the response from GitHub's releases API
 type GitHubRelease struct {
-	TagName string `json:"tag_name"`
-	Name    string `json:"name"`
+	TagName    string         `json:"tag_name"`
+	Name       string         `json:"name"`
+	Prerelease bool           `json:"prerelease"`
+	Draft      bool           `json:"draft"`
+	Assets     []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a file attached to a release
+type ReleaseAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
 }
 
 // UpdateInfo contains information about available updates
@@ -25,7 +36,45 @@ type UpdateInfo struct {
 	UpdateMessage  string
 }
 
-// checkForUpdates checks GitHub API for the latest release
+// Releases API of the starter repository, overridable for testing against a local server
+const defaultUpdateEndpoint = "https://api.github.com/repos/philipptpunkt/ledger-live-starter"
+
+// Update check settings from the environment
+const (
+	noUpdateCheckEnv  = "LEDGER_LIVE_STARTER_NO_UPDATE_CHECK"
+	updateEndpointEnv = "LEDGER_LIVE_STARTER_UPDATE_ENDPOINT"
+	updateChannelEnv  = "LEDGER_LIVE_STARTER_UPDATE_CHANNEL"
+)
+
+// Number of releases read from the releases list when looking for a channel's newest
+const releasesPerPage = 100
+
+// The latest release is cached in ~/.ledger-live and refreshed in the background once stale
+const (
+	updateCacheFileName = "update-check.json"
+	updateCacheTTL      = 24 * time.Hour
+	updateCheckTimeout  = 5 * time.Second
+)
+
+// updateCache is the last update check result
+type updateCache struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	Endpoint      string    `json:"endpoint"`
+	Channel       string    `json:"channel"`
+	LatestVersion string    `json:"latest_version"`
+}
+
+// Set with --no-update-check
+var noUpdateCheck bool
+
+// Closed when a background refresh started by checkForUpdates is done
+var updateRefreshDone chan struct{}
+
+// Longest time the process waits on exit for a background refresh to finish
+const updateRefreshGrace = 2 * time.Second
+
+// checkForUpdates reports an update from the cached check result without waiting for the
+// network. When the cache is stale, a fresh check runs in the background for the next start.
 func checkForUpdates() *UpdateInfo {
 	info := &UpdateInfo{
 		CurrentVersion: strings.TrimPrefix(Version, "v"),
@@ -37,82 +86,213 @@ func checkForUpdates() *UpdateInfo {
 		return info
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 3 * time.Second, // Quick timeout to avoid blocking startup
-	}
-
-	// Make request to GitHub API
-	resp, err := client.Get("https://api.github.com/repos/philipptpunkt/ledger-live-starter/releases/latest")
-	if err != nil {
-		// Silently fail - don't show errors for update checks
+	settings := updateCheckSettings()
+	if !settings.Enabled {
 		return info
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return info
+	cache := readUpdateCache()
+	current := cache != nil && cache.Endpoint == settings.Endpoint && cache.Channel == settings.Channel
+	if !current || time.Since(cache.CheckedAt) > updateCacheTTL {
+		updateRefreshDone = make(chan struct{})
+		go func() {
+			defer close(updateRefreshDone)
+			refreshUpdateCache(settings.Endpoint, settings.Channel)
+		}()
 	}
-
-	// Parse response
-	var release GitHubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+	if !current {
 		return info
 	}
 
 	// Clean version strings for comparison
-	latestVersion := strings.TrimPrefix(release.TagName, "v")
-	currentVersion := strings.TrimPrefix(Version, "v")
-
-	info.LatestVersion = latestVersion
+	info.LatestVersion = strings.TrimPrefix(cache.LatestVersion, "v")
 
 	// Compare versions
-	if isNewerVersion(latestVersion, currentVersion) {
+	if isNewerVersion(info.LatestVersion, info.CurrentVersion) {
 		info.HasUpdate = true
-		info.UpdateMessage = createUpdateMessage(latestVersion)
+		info.UpdateMessage = createUpdateMessage(info.LatestVersion)
 	}
 
 	return info
 }
 
-// isNewerVersion compares two semantic version strings
-func isNewerVersion(latest, current string) bool {
-	// Simple version comparison - split by dots and compare each part
-	latestParts := strings.Split(latest, ".")
-	currentParts := strings.Split(current, ".")
+// waitForUpdateRefresh gives a running background refresh a moment to finish before the
+// process exits, so short runs still update the cache
+func waitForUpdateRefresh() {
+	if updateRefreshDone == nil {
+		return
+	}
+	select {
+	case <-updateRefreshDone:
+	case <-time.After(updateRefreshGrace):
+	}
+}
 
-	// Pad shorter version with zeros
-	maxLen := len(latestParts)
-	if len(currentParts) > maxLen {
-		maxLen = len(currentParts)
+// updateSettings configures the update check and self-update
+type updateSettings struct {
+	Endpoint string // Releases API base URL
+	Channel  string // setup.ChannelStable or setup.ChannelBeta
+	Enabled  bool   // Whether start checks for updates
+}
+
+// updateCheckSettings reads the update settings from the flag, the environment and the
+// config, in that order
+func updateCheckSettings() updateSettings {
+	config, _ := setup.PeekConfig()
+
+	settings := updateSettings{
+		Endpoint: defaultUpdateEndpoint,
+		Channel:  setup.ChannelStable,
+		Enabled:  !noUpdateCheck && os.Getenv(noUpdateCheckEnv) == "",
 	}
+	if config != nil {
+		if !config.UpdateCheckEnabled() {
+			settings.Enabled = false
+		}
+		if config.UpdateEndpoint != "" {
+			settings.Endpoint = config.UpdateEndpoint
+		}
+		settings.Channel = config.UpdateChannelOrDefault()
+	}
+	if value := os.Getenv(updateEndpointEnv); value != "" {
+		settings.Endpoint = value
+	}
+	if value := os.Getenv(updateChannelEnv); value != "" {
+		settings.Channel = value
+	}
+	settings.Endpoint = strings.TrimSuffix(settings.Endpoint, "/")
+	return settings
+}
 
-	for len(latestParts) < maxLen {
-		latestParts = append(latestParts, "0")
+// validateUpdateChannel rejects unknown release channels
+func validateUpdateChannel(channel string) error {
+	if channel != setup.ChannelStable && channel != setup.ChannelBeta {
+		return fmt.Errorf("unknown update channel '%s', must be %s or %s", channel, setup.ChannelStable, setup.ChannelBeta)
 	}
-	for len(currentParts) < maxLen {
-		currentParts = append(currentParts, "0")
+	return nil
+}
+
+// fetchChannelRelease returns the newest published release on a channel. The stable channel
+// skips pre-releases, beta takes whichever version is highest.
+func fetchChannelRelease(endpoint, channel string) (*GitHubRelease, error) {
+	if err := validateUpdateChannel(channel); err != nil {
+		return nil, err
+	}
+	releases, err := fetchReleases(endpoint)
+	if err != nil {
+		return nil, err
 	}
 
-	// Compare each part
-	for i := 0; i < maxLen; i++ {
-		if latestParts[i] > currentParts[i] {
-			return true
-		} else if latestParts[i] < currentParts[i] {
-			return false
+	var newest *GitHubRelease
+	var newestVersion semVersion
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft || (release.Prerelease && channel != setup.ChannelBeta) {
+			continue
+		}
+		version, ok := parseSemver(release.TagName)
+		if !ok {
+			continue
 		}
+		if newest == nil || compareSemver(version, newestVersion) > 0 {
+			newest, newestVersion = release, version
+		}
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("no %s release found", channel)
+	}
+	return newest, nil
+}
+
+// fetchReleases reads the most recent releases from the releases API
+func fetchReleases(endpoint string) ([]GitHubRelease, error) {
+	var releases []GitHubRelease
+	if err := getReleaseJSON(fmt.Sprintf("%s/releases?per_page=%d", endpoint, releasesPerPage), &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// fetchRelease reads release metadata from the releases API
+func fetchRelease(url string) (*GitHubRelease, error) {
+	var release GitHubRelease
+	if err := getReleaseJSON(url, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// getReleaseJSON GETs a releases API URL and decodes the response into v
+func getReleaseJSON(url string, v any) error {
+	client := &http.Client{Timeout: updateCheckTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("release check failed: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// refreshUpdateCache checks for the newest release on the channel and caches the result.
+// Failures are silent, the next start tries again.
+func refreshUpdateCache(endpoint, channel string) {
+	release, err := fetchChannelRelease(endpoint, channel)
+	if err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(updateCache{
+		CheckedAt:     time.Now(),
+		Endpoint:      endpoint,
+		Channel:       channel,
+		LatestVersion: release.TagName,
+	}, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := setup.EnsureConfigDirExists(); err != nil {
+		return
+	}
+	setup.WriteFileAtomic(updateCachePath(), data, 0644)
+}
+
+// readUpdateCache returns the cached check result, nil if there is none
+func readUpdateCache() *updateCache {
+	data, err := os.ReadFile(updateCachePath())
+	if err != nil {
+		return nil
 	}
+	var cache updateCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil
+	}
+	return &cache
+}
 
-	return false
+func updateCachePath() string {
+	return filepath.Join(setup.GetLedgerLiveDir(), updateCacheFileName)
+}
+
+// isNewerVersion compares two semantic version strings, including pre-release tags
+func isNewerVersion(latest, current string) bool {
+	latestVersion, ok := parseSemver(latest)
+	if !ok {
+		return false
+	}
+	currentVersion, ok := parseSemver(current)
+	if !ok {
+		return false
+	}
+	return compareSemver(latestVersion, currentVersion) > 0
 }
 
 // createUpdateMessage creates a styled update notification message
 func createUpdateMessage(latestVersion string) string {
-	// Use same amber/orange color for both lines
-	updateColor := lipgloss.AdaptiveColor{
-		Light: "#f59e0b", // Amber for light backgrounds
-		Dark:  "#fbbf24", // Lighter amber for dark backgrounds
-	}
+	// Use the theme's warning color for both lines
+	updateColor := palette.Warning
 
 	// Create styles with same color
 	updateStyle := lipgloss.NewStyle().
@@ -125,14 +305,7 @@ func createUpdateMessage(latestVersion string) string {
 		Align(lipgloss.Center).
 		MarginLeft(4)
 
-	// Determine OS-specific update command
-	var updateCommand string
-	switch runtime.GOOS {
-	case "windows":
-		updateCommand = "iwr -useb https://raw.githubusercontent.com/philipptpunkt/ledger-live-starter/refs/heads/main/scripts/install.ps1 | iex"
-	default:
-		updateCommand = "curl -fsSL https://raw.githubusercontent.com/philipptpunkt/ledger-live-starter/refs/heads/main/scripts/install.sh | bash"
-	}
+	updateCommand := "ledger-live self-update"
 
 	// Build multi-line message
 	var message strings.Builder
@@ -145,7 +318,7 @@ func createUpdateMessage(latestVersion string) string {
 
 // getVersionOrUpdateDisplay returns either version info or update notification
 func getVersionOrUpdateDisplay() string {
-	// Check for updates (with timeout)
+	// Check for updates (from the cache, refreshed in the background)
 	updateInfo := checkForUpdates()
 
 	if updateInfo.HasUpdate {
@@ -153,13 +326,8 @@ func getVersionOrUpdateDisplay() string {
 	}
 
 	// Fall back to showing current version
-	textColor := lipgloss.AdaptiveColor{
-		Light: "#1a1a1a", // Dark text for light backgrounds
-		Dark:  "#ffffff", // White text for dark backgrounds
-	}
-
 	versionStyle := lipgloss.NewStyle().
-		Foreground(textColor).
+		Foreground(palette.Text).
 		Align(lipgloss.Center).
 		MarginLeft(4)
 
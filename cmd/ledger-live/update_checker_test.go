@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ledger-live-starter/cmd/ledger-live/setup"
+)
+
+// releasesServer serves a releases list like GitHub's releases API
+func releasesServer(t *testing.T, releases []GitHubRelease) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(releases)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetchChannelRelease(t *testing.T) {
+	server := releasesServer(t, []GitHubRelease{
+		{TagName: "v1.9.0"},
+		{TagName: "v1.10.0"},
+		{TagName: "v1.11.0-beta.1", Prerelease: true},
+		{TagName: "v2.0.0", Draft: true},
+		{TagName: "nightly"},
+	})
+
+	tests := []struct {
+		channel string
+		want    string
+	}{
+		{setup.ChannelStable, "v1.10.0"},
+		{setup.ChannelBeta, "v1.11.0-beta.1"},
+	}
+	for _, tt := range tests {
+		release, err := fetchChannelRelease(server.URL, tt.channel)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.channel, err)
+		}
+		if release.TagName != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.channel, release.TagName, tt.want)
+		}
+	}
+
+	if _, err := fetchChannelRelease(server.URL, "nightly"); err == nil {
+		t.Error("expected an unknown channel to be rejected")
+	}
+}
+
+func TestFetchChannelReleaseErrors(t *testing.T) {
+	empty := releasesServer(t, []GitHubRelease{{TagName: "v1.0.0-rc.1", Prerelease: true}})
+	if _, err := fetchChannelRelease(empty.URL, setup.ChannelStable); err == nil {
+		t.Error("expected an error when the channel has no release")
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer failing.Close()
+	if _, err := fetchChannelRelease(failing.URL, setup.ChannelStable); err == nil {
+		t.Error("expected an error on a non-200 response")
+	}
+}
+
+func TestRefreshUpdateCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := releasesServer(t, []GitHubRelease{{TagName: "v1.2.0"}, {TagName: "v1.3.0-beta.1", Prerelease: true}})
+
+	refreshUpdateCache(server.URL, setup.ChannelBeta)
+
+	cache := readUpdateCache()
+	if cache == nil {
+		t.Fatal("no update cache written")
+	}
+	if cache.LatestVersion != "v1.3.0-beta.1" || cache.Endpoint != server.URL || cache.Channel != setup.ChannelBeta {
+		t.Errorf("unexpected cache: %+v", cache)
+	}
+	if cache.CheckedAt.IsZero() {
+		t.Error("cache has no check time")
+	}
+}
+
+func TestRefreshUpdateCacheKeepsCacheOnFailure(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := releasesServer(t, []GitHubRelease{{TagName: "v1.2.0"}})
+	refreshUpdateCache(server.URL, setup.ChannelStable)
+
+	server.Close()
+	refreshUpdateCache(server.URL, setup.ChannelStable)
+
+	if cache := readUpdateCache(); cache == nil || cache.LatestVersion != "v1.2.0" {
+		t.Errorf("a failed refresh must keep the previous result, got %+v", cache)
+	}
+}
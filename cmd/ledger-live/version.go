@@ -19,14 +19,55 @@ var versionCmd = &cobra.Command{
 	Short: "Print the version number",
 	Long:  `Print the version number of ledger-live-starter`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("%s %s\n", TitleText("Ledger Live Starter"), HighlightText(Version))
-		fmt.Printf("  %s %s\n", InfoTextTitle("Built:"), NormalText(BuildTime))
-		fmt.Printf("  %s %s\n", InfoTextTitle("Go:"), NormalText(runtime.Version()))
-		fmt.Printf("  %s %s/%s\n", InfoTextTitle("Platform:"), NormalText(runtime.GOOS), NormalText(runtime.GOARCH))
-		fmt.Printf("  %s %s\n", InfoTextTitle("Config:"), NormalText(setup.GetConfigPath()))
+		format := selectedOutputFormat()
+		output := VersionOutput{
+			Version:   Version,
+			BuildTime: BuildTime,
+			GoVersion: runtime.Version(),
+			Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+			Channel:   updateCheckSettings().Channel,
+			Profile:   setup.ActiveProfile(),
+			Config:    setup.GetConfigPath(),
+		}
+
+		printOutput(format, output, func() {
+			if format == OutputPlain {
+				printFields([][2]string{
+					{"version", output.Version},
+					{"built", output.BuildTime},
+					{"go", output.GoVersion},
+					{"platform", output.Platform},
+					{"channel", output.Channel},
+					{"profile", output.Profile},
+					{"config", output.Config},
+				})
+				return
+			}
+			fmt.Printf("%s %s\n", TitleText("Ledger Live Starter"), HighlightText(output.Version))
+			fmt.Printf("  %s %s\n", InfoTextTitle("Built:"), NormalText(output.BuildTime))
+			fmt.Printf("  %s %s\n", InfoTextTitle("Go:"), NormalText(output.GoVersion))
+			fmt.Printf("  %s %s\n", InfoTextTitle("Platform:"), NormalText(output.Platform))
+			fmt.Printf("  %s %s\n", InfoTextTitle("Channel:"), NormalText(output.Channel))
+			if output.Profile != "" {
+				fmt.Printf("  %s %s\n", InfoTextTitle("Profile:"), NormalText(output.Profile))
+			}
+			fmt.Printf("  %s %s\n", InfoTextTitle("Config:"), NormalText(output.Config))
+		})
 	},
 }
 
+// VersionOutput is the machine-readable output of `version`
+type VersionOutput struct {
+	Version   string `json:"version" yaml:"version"`
+	BuildTime string `json:"build_time" yaml:"build_time"`
+	GoVersion string `json:"go_version" yaml:"go_version"`
+	Platform  string `json:"platform" yaml:"platform"` // GOOS/GOARCH
+	Channel   string `json:"channel" yaml:"channel"`   // Update channel, stable or beta
+	Profile   string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Config    string `json:"config" yaml:"config"`
+}
+
 func init() {
+	addOutputFlags(versionCmd)
 	rootCmd.AddCommand(versionCmd)
 }